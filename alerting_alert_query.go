@@ -0,0 +1,542 @@
+package gapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// ExpressionDatasourceUID is the UID of the data source running server-side expressions.
+const ExpressionDatasourceUID = "__expr__"
+
+// legacyExpressionDatasourceUID is the UID used for server-side expressions by older Grafana versions.
+const legacyExpressionDatasourceUID = "-100"
+
+// AlertQueryModel is implemented by the typed models of alert query stages, which can be used as AlertQuery.Model.
+type AlertQueryModel interface {
+	// Validate returns an error if the model is not valid.
+	Validate() error
+}
+
+// AlertExpressionModel is implemented by the typed models of server-side expressions.
+type AlertExpressionModel interface {
+	AlertQueryModel
+	// ExpressionType returns the type of the expression, e.g. "math".
+	ExpressionType() string
+	// Inputs returns the ref IDs of the query stages the expression depends on.
+	Inputs() []string
+}
+
+// Server-side expression types.
+const (
+	ExpressionTypeMath              = "math"
+	ExpressionTypeReduce            = "reduce"
+	ExpressionTypeResample          = "resample"
+	ExpressionTypeThreshold         = "threshold"
+	ExpressionTypeClassicConditions = "classic_conditions"
+)
+
+// Reducers of reduce expressions.
+const (
+	ExpressionReducerLast  = "last"
+	ExpressionReducerMean  = "mean"
+	ExpressionReducerMin   = "min"
+	ExpressionReducerMax   = "max"
+	ExpressionReducerSum   = "sum"
+	ExpressionReducerCount = "count"
+)
+
+// Modes of reduce expressions, defining how non-numeric values are handled.
+const (
+	// ReduceModeStrict returns NaN if the series contains any non-numeric value.
+	ReduceModeStrict    = ""
+	ReduceModeDropNN    = "dropNN"
+	ReduceModeReplaceNN = "replaceNN"
+)
+
+// Evaluator types of threshold expressions and classic conditions.
+const (
+	EvaluatorGreaterThan  = "gt"
+	EvaluatorLessThan     = "lt"
+	EvaluatorWithinRange  = "within_range"
+	EvaluatorOutsideRange = "outside_range"
+	// EvaluatorNoValue is only supported by classic conditions.
+	EvaluatorNoValue = "no_value"
+)
+
+// NewAlertQuery returns the query stage running the model against the data source whose UID it's passed
+// over the relative time range.
+func NewAlertQuery(refID, datasourceUID string, timeRange RelativeTimeRange, model AlertQueryModel) *AlertQuery {
+	return &AlertQuery{
+		DatasourceUID:     datasourceUID,
+		Model:             model,
+		RefID:             refID,
+		RelativeTimeRange: timeRange,
+	}
+}
+
+// NewExpressionQuery returns the query stage running the server-side expression.
+func NewExpressionQuery(refID string, model AlertExpressionModel) *AlertQuery {
+	return &AlertQuery{
+		DatasourceUID: ExpressionDatasourceUID,
+		Model:         model,
+		RefID:         refID,
+	}
+}
+
+// ValidateQueries checks the query stages of the alert rule: ref IDs must be unique, the condition and the
+// inputs of every expression must refer to existing stages, and typed models must be valid.
+func (rule *AlertRule) ValidateQueries() error {
+	if len(rule.Data) == 0 {
+		return fmt.Errorf("alert rule %s has no queries", rule.Title)
+	}
+
+	refIDs := make(map[string]bool, len(rule.Data))
+	for _, query := range rule.Data {
+		if query.RefID == "" {
+			return fmt.Errorf("alert rule %s has a query without ref ID", rule.Title)
+		}
+		if refIDs[query.RefID] {
+			return fmt.Errorf("alert rule %s has duplicate ref ID %s", rule.Title, query.RefID)
+		}
+		refIDs[query.RefID] = true
+	}
+
+	for _, query := range rule.Data {
+		model, ok := query.Model.(AlertQueryModel)
+		if !ok {
+			continue
+		}
+		if err := model.Validate(); err != nil {
+			return fmt.Errorf("query %s: %w", query.RefID, err)
+		}
+		expression, ok := model.(AlertExpressionModel)
+		if !ok {
+			continue
+		}
+		if query.DatasourceUID != ExpressionDatasourceUID && query.DatasourceUID != legacyExpressionDatasourceUID {
+			return fmt.Errorf("query %s: expressions must use data source %s", query.RefID, ExpressionDatasourceUID)
+		}
+		for _, input := range expression.Inputs() {
+			if input == query.RefID {
+				return fmt.Errorf("query %s: expression refers to itself", query.RefID)
+			}
+			if !refIDs[input] {
+				return fmt.Errorf("query %s: unknown input %s", query.RefID, input)
+			}
+		}
+	}
+
+	if !refIDs[rule.Condition] {
+		return fmt.Errorf("alert rule %s: condition %q does not refer to a query", rule.Title, rule.Condition)
+	}
+
+	return nil
+}
+
+// PrometheusAlertQueryModel represents a query against a Prometheus data source.
+type PrometheusAlertQueryModel struct {
+	Expr string
+	// Instant runs an instant query, a range query is run otherwise.
+	Instant       bool
+	LegendFormat  string
+	IntervalMs    int64
+	MaxDataPoints int64
+}
+
+// Validate implements the AlertQueryModel interface.
+func (m PrometheusAlertQueryModel) Validate() error {
+	if m.Expr == "" {
+		return fmt.Errorf("expr is required")
+	}
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface for PrometheusAlertQueryModel.
+func (m PrometheusAlertQueryModel) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Expr          string `json:"expr"`
+		Instant       bool   `json:"instant"`
+		Range         bool   `json:"range"`
+		LegendFormat  string `json:"legendFormat,omitempty"`
+		IntervalMs    int64  `json:"intervalMs,omitempty"`
+		MaxDataPoints int64  `json:"maxDataPoints,omitempty"`
+	}{
+		Expr:          m.Expr,
+		Instant:       m.Instant,
+		Range:         !m.Instant,
+		LegendFormat:  m.LegendFormat,
+		IntervalMs:    m.IntervalMs,
+		MaxDataPoints: m.MaxDataPoints,
+	})
+}
+
+// LokiAlertQueryModel represents a query against a Loki data source.
+type LokiAlertQueryModel struct {
+	Expr string
+	// Instant runs an instant query, a range query is run otherwise.
+	Instant       bool
+	LegendFormat  string
+	IntervalMs    int64
+	MaxDataPoints int64
+}
+
+// Validate implements the AlertQueryModel interface.
+func (m LokiAlertQueryModel) Validate() error {
+	if m.Expr == "" {
+		return fmt.Errorf("expr is required")
+	}
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface for LokiAlertQueryModel.
+func (m LokiAlertQueryModel) MarshalJSON() ([]byte, error) {
+	queryType := "range"
+	if m.Instant {
+		queryType = "instant"
+	}
+	return json.Marshal(struct {
+		Expr          string `json:"expr"`
+		QueryType     string `json:"queryType"`
+		LegendFormat  string `json:"legendFormat,omitempty"`
+		IntervalMs    int64  `json:"intervalMs,omitempty"`
+		MaxDataPoints int64  `json:"maxDataPoints,omitempty"`
+	}{
+		Expr:          m.Expr,
+		QueryType:     queryType,
+		LegendFormat:  m.LegendFormat,
+		IntervalMs:    m.IntervalMs,
+		MaxDataPoints: m.MaxDataPoints,
+	})
+}
+
+// expressionModel holds the properties shared by every server-side expression model.
+type expressionModel struct {
+	Type       string            `json:"type"`
+	Datasource map[string]string `json:"datasource"`
+	Expression string            `json:"expression,omitempty"`
+}
+
+func newExpressionModel(expressionType, expression string) expressionModel {
+	return expressionModel{
+		Type: expressionType,
+		Datasource: map[string]string{
+			"type": ExpressionDatasourceUID,
+			"uid":  ExpressionDatasourceUID,
+		},
+		Expression: expression,
+	}
+}
+
+// MathExpression represents a math expression, e.g. "$A * 100 > $B".
+type MathExpression struct {
+	Expression string
+}
+
+var mathExpressionVariable = regexp.MustCompile(`\$(?:\{([^}]+)\}|([A-Za-z0-9_]+))`)
+
+// ExpressionType implements the AlertExpressionModel interface.
+func (e MathExpression) ExpressionType() string {
+	return ExpressionTypeMath
+}
+
+// Inputs implements the AlertExpressionModel interface, returning the variables of the expression.
+func (e MathExpression) Inputs() []string {
+	inputs := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, match := range mathExpressionVariable.FindAllStringSubmatch(e.Expression, -1) {
+		input := match[1]
+		if input == "" {
+			input = match[2]
+		}
+		if !seen[input] {
+			seen[input] = true
+			inputs = append(inputs, input)
+		}
+	}
+	return inputs
+}
+
+// Validate implements the AlertQueryModel interface.
+func (e MathExpression) Validate() error {
+	if e.Expression == "" {
+		return fmt.Errorf("math expression is required")
+	}
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface for MathExpression.
+func (e MathExpression) MarshalJSON() ([]byte, error) {
+	return json.Marshal(newExpressionModel(ExpressionTypeMath, e.Expression))
+}
+
+// ReduceExpression represents an expression reducing every series of its input to a single number.
+type ReduceExpression struct {
+	// Input is the ref ID of the query stage to reduce.
+	Input   string
+	Reducer string
+	Mode    string
+	// ReplaceWithValue replaces non-numeric values when Mode is ReduceModeReplaceNN.
+	ReplaceWithValue float64
+}
+
+// ExpressionType implements the AlertExpressionModel interface.
+func (e ReduceExpression) ExpressionType() string {
+	return ExpressionTypeReduce
+}
+
+// Inputs implements the AlertExpressionModel interface.
+func (e ReduceExpression) Inputs() []string {
+	return []string{e.Input}
+}
+
+// Validate implements the AlertQueryModel interface.
+func (e ReduceExpression) Validate() error {
+	if e.Input == "" {
+		return fmt.Errorf("reduce input is required")
+	}
+	if err := validateRequiredOneOf("reducer", e.Reducer, ExpressionReducerLast, ExpressionReducerMean, ExpressionReducerMin,
+		ExpressionReducerMax, ExpressionReducerSum, ExpressionReducerCount); err != nil {
+		return err
+	}
+	return validateOneOf("reduce mode", e.Mode, ReduceModeStrict, ReduceModeDropNN, ReduceModeReplaceNN)
+}
+
+// MarshalJSON implements the json.Marshaler interface for ReduceExpression.
+func (e ReduceExpression) MarshalJSON() ([]byte, error) {
+	type settings struct {
+		Mode             string   `json:"mode"`
+		ReplaceWithValue *float64 `json:"replaceWithValue,omitempty"`
+	}
+	model := struct {
+		expressionModel
+		Reducer  string    `json:"reducer"`
+		Settings *settings `json:"settings,omitempty"`
+	}{
+		expressionModel: newExpressionModel(ExpressionTypeReduce, e.Input),
+		Reducer:         e.Reducer,
+	}
+	if e.Mode != ReduceModeStrict {
+		model.Settings = &settings{Mode: e.Mode}
+		if e.Mode == ReduceModeReplaceNN {
+			model.Settings.ReplaceWithValue = &e.ReplaceWithValue
+		}
+	}
+	return json.Marshal(model)
+}
+
+// ResampleExpression represents an expression changing the timestamps of its input series to a regular interval.
+type ResampleExpression struct {
+	// Input is the ref ID of the query stage to resample.
+	Input string
+	// Window is the interval of the resampled series, e.g. "1m".
+	Window string
+	// Downsampler is the reducer used when there are several values per window, e.g. "mean".
+	Downsampler string
+	// Upsampler fills windows without value, either "pad", "backfilling" or "fillna".
+	Upsampler string
+}
+
+// ExpressionType implements the AlertExpressionModel interface.
+func (e ResampleExpression) ExpressionType() string {
+	return ExpressionTypeResample
+}
+
+// Inputs implements the AlertExpressionModel interface.
+func (e ResampleExpression) Inputs() []string {
+	return []string{e.Input}
+}
+
+// Validate implements the AlertQueryModel interface.
+func (e ResampleExpression) Validate() error {
+	if e.Input == "" {
+		return fmt.Errorf("resample input is required")
+	}
+	if e.Window == "" {
+		return fmt.Errorf("resample window is required")
+	}
+	if err := validateRequiredOneOf("downsampler", e.Downsampler, ExpressionReducerLast, ExpressionReducerMean,
+		ExpressionReducerMin, ExpressionReducerMax, ExpressionReducerSum); err != nil {
+		return err
+	}
+	return validateRequiredOneOf("upsampler", e.Upsampler, "pad", "backfilling", "fillna")
+}
+
+// MarshalJSON implements the json.Marshaler interface for ResampleExpression.
+func (e ResampleExpression) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		expressionModel
+		Window      string `json:"window"`
+		Downsampler string `json:"downsampler"`
+		Upsampler   string `json:"upsampler"`
+	}{
+		expressionModel: newExpressionModel(ExpressionTypeResample, e.Input),
+		Window:          e.Window,
+		Downsampler:     e.Downsampler,
+		Upsampler:       e.Upsampler,
+	})
+}
+
+// ThresholdExpression represents an expression checking whether the values of its input satisfy an evaluator.
+type ThresholdExpression struct {
+	// Input is the ref ID of the query stage to check.
+	Input     string
+	Evaluator string
+	// Params holds a single threshold, or the two bounds of range evaluators.
+	Params []float64
+}
+
+// ExpressionType implements the AlertExpressionModel interface.
+func (e ThresholdExpression) ExpressionType() string {
+	return ExpressionTypeThreshold
+}
+
+// Inputs implements the AlertExpressionModel interface.
+func (e ThresholdExpression) Inputs() []string {
+	return []string{e.Input}
+}
+
+// Validate implements the AlertQueryModel interface.
+func (e ThresholdExpression) Validate() error {
+	if e.Input == "" {
+		return fmt.Errorf("threshold input is required")
+	}
+	if err := validateRequiredOneOf("evaluator", e.Evaluator, EvaluatorGreaterThan, EvaluatorLessThan,
+		EvaluatorWithinRange, EvaluatorOutsideRange); err != nil {
+		return err
+	}
+	return validateEvaluatorParams(e.Evaluator, e.Params)
+}
+
+// MarshalJSON implements the json.Marshaler interface for ThresholdExpression.
+func (e ThresholdExpression) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		expressionModel
+		Conditions []map[string]interface{} `json:"conditions"`
+	}{
+		expressionModel: newExpressionModel(ExpressionTypeThreshold, e.Input),
+		Conditions: []map[string]interface{}{
+			{"evaluator": evaluatorModel(e.Evaluator, e.Params)},
+		},
+	})
+}
+
+// ClassicCondition represents a single condition of a classic conditions expression,
+// e.g. "avg() of A is above 10".
+type ClassicCondition struct {
+	// Query is the ref ID of the query stage the condition applies to.
+	Query string
+	// Reducer is one of avg, min, max, sum, count, last, median, diff, diff_abs, percent_diff,
+	// percent_diff_abs or count_non_null.
+	Reducer   string
+	Evaluator string
+	Params    []float64
+	// Operator combines the condition with the previous ones, either "and" or "or". It defaults to "and".
+	Operator string
+}
+
+// ClassicConditionsExpression represents an expression behaving like the conditions of legacy dashboard alerts.
+type ClassicConditionsExpression struct {
+	Conditions []ClassicCondition
+}
+
+// ExpressionType implements the AlertExpressionModel interface.
+func (e ClassicConditionsExpression) ExpressionType() string {
+	return ExpressionTypeClassicConditions
+}
+
+// Inputs implements the AlertExpressionModel interface.
+func (e ClassicConditionsExpression) Inputs() []string {
+	inputs := make([]string, 0, len(e.Conditions))
+	seen := make(map[string]bool)
+	for _, condition := range e.Conditions {
+		if !seen[condition.Query] {
+			seen[condition.Query] = true
+			inputs = append(inputs, condition.Query)
+		}
+	}
+	return inputs
+}
+
+// Validate implements the AlertQueryModel interface.
+func (e ClassicConditionsExpression) Validate() error {
+	if len(e.Conditions) == 0 {
+		return fmt.Errorf("at least one classic condition is required")
+	}
+	for i, condition := range e.Conditions {
+		if condition.Query == "" {
+			return fmt.Errorf("condition %d: query is required", i)
+		}
+		if err := validateRequiredOneOf("reducer", condition.Reducer, "avg", "min", "max", "sum", "count", "last", "median",
+			"diff", "diff_abs", "percent_diff", "percent_diff_abs", "count_non_null"); err != nil {
+			return fmt.Errorf("condition %d: %w", i, err)
+		}
+		if err := validateRequiredOneOf("evaluator", condition.Evaluator, EvaluatorGreaterThan, EvaluatorLessThan,
+			EvaluatorWithinRange, EvaluatorOutsideRange, EvaluatorNoValue); err != nil {
+			return fmt.Errorf("condition %d: %w", i, err)
+		}
+		if err := validateEvaluatorParams(condition.Evaluator, condition.Params); err != nil {
+			return fmt.Errorf("condition %d: %w", i, err)
+		}
+		if err := validateOneOf("operator", condition.Operator, "", "and", "or"); err != nil {
+			return fmt.Errorf("condition %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface for ClassicConditionsExpression.
+func (e ClassicConditionsExpression) MarshalJSON() ([]byte, error) {
+	conditions := make([]map[string]interface{}, len(e.Conditions))
+	for i, condition := range e.Conditions {
+		operator := condition.Operator
+		if operator == "" {
+			operator = "and"
+		}
+		conditions[i] = map[string]interface{}{
+			"type":      "query",
+			"query":     map[string]interface{}{"params": []string{condition.Query}},
+			"reducer":   map[string]interface{}{"type": condition.Reducer, "params": []float64{}},
+			"evaluator": evaluatorModel(condition.Evaluator, condition.Params),
+			"operator":  map[string]string{"type": operator},
+		}
+	}
+	return json.Marshal(struct {
+		expressionModel
+		Conditions []map[string]interface{} `json:"conditions"`
+	}{
+		expressionModel: newExpressionModel(ExpressionTypeClassicConditions, ""),
+		Conditions:      conditions,
+	})
+}
+
+func evaluatorModel(evaluator string, params []float64) map[string]interface{} {
+	if params == nil {
+		params = []float64{}
+	}
+	return map[string]interface{}{
+		"type":   evaluator,
+		"params": params,
+	}
+}
+
+func validateRequiredOneOf(setting, value string, allowed ...string) error {
+	if value == "" {
+		return fmt.Errorf("%s is required", setting)
+	}
+	return validateOneOf(setting, value, allowed...)
+}
+
+func validateEvaluatorParams(evaluator string, params []float64) error {
+	expected := 1
+	switch evaluator {
+	case EvaluatorWithinRange, EvaluatorOutsideRange:
+		expected = 2
+	case EvaluatorNoValue:
+		expected = 0
+	}
+	if len(params) != expected {
+		return fmt.Errorf("evaluator %s expects %d params, got %d", evaluator, expected, len(params))
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package gapi
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAlertQueryModelsMarshal(t *testing.T) {
+	cases := []struct {
+		name     string
+		model    AlertQueryModel
+		expected string
+	}{
+		{
+			name:     "prometheus range",
+			model:    PrometheusAlertQueryModel{Expr: "up", IntervalMs: 1000},
+			expected: `{"expr":"up","instant":false,"range":true,"intervalMs":1000}`,
+		},
+		{
+			name:     "prometheus instant",
+			model:    PrometheusAlertQueryModel{Expr: "up", Instant: true},
+			expected: `{"expr":"up","instant":true,"range":false}`,
+		},
+		{
+			name:     "loki instant",
+			model:    LokiAlertQueryModel{Expr: `count_over_time({app="a"}[5m])`, Instant: true},
+			expected: `{"expr":"count_over_time({app=\"a\"}[5m])","queryType":"instant"}`,
+		},
+		{
+			name:     "math",
+			model:    MathExpression{Expression: "$B * 2"},
+			expected: `{"type":"math","datasource":{"type":"__expr__","uid":"__expr__"},"expression":"$B * 2"}`,
+		},
+		{
+			name:     "reduce",
+			model:    ReduceExpression{Input: "A", Reducer: ExpressionReducerLast, Mode: ReduceModeReplaceNN},
+			expected: `{"type":"reduce","datasource":{"type":"__expr__","uid":"__expr__"},"expression":"A","reducer":"last","settings":{"mode":"replaceNN","replaceWithValue":0}}`,
+		},
+		{
+			name:     "resample",
+			model:    ResampleExpression{Input: "A", Window: "1m", Downsampler: "mean", Upsampler: "fillna"},
+			expected: `{"type":"resample","datasource":{"type":"__expr__","uid":"__expr__"},"expression":"A","window":"1m","downsampler":"mean","upsampler":"fillna"}`,
+		},
+		{
+			name:     "threshold",
+			model:    ThresholdExpression{Input: "B", Evaluator: EvaluatorOutsideRange, Params: []float64{1, 10}},
+			expected: `{"type":"threshold","datasource":{"type":"__expr__","uid":"__expr__"},"expression":"B","conditions":[{"evaluator":{"params":[1,10],"type":"outside_range"}}]}`,
+		},
+		{
+			name: "classic conditions",
+			model: ClassicConditionsExpression{Conditions: []ClassicCondition{
+				{Query: "A", Reducer: "avg", Evaluator: EvaluatorGreaterThan, Params: []float64{3}},
+			}},
+			expected: `{"type":"classic_conditions","datasource":{"type":"__expr__","uid":"__expr__"},"conditions":[{"evaluator":{"params":[3],"type":"gt"},"operator":{"type":"and"},"query":{"params":["A"]},"reducer":{"params":[],"type":"avg"},"type":"query"}]}`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := c.model.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			data, err := json.Marshal(c.model)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != c.expected {
+				t.Errorf("Unexpected model JSON:\n%s\nexpected:\n%s", data, c.expected)
+			}
+		})
+	}
+}
+
+func TestAlertQueryModelsValidate(t *testing.T) {
+	cases := map[string]AlertQueryModel{
+		"missing expr":             PrometheusAlertQueryModel{},
+		"unknown reducer":          ReduceExpression{Input: "A", Reducer: "median"},
+		"missing reducer":          ReduceExpression{Input: "A"},
+		"unknown upsampler":        ResampleExpression{Input: "A", Window: "1m", Downsampler: "mean", Upsampler: "zero"},
+		"range with one param":     ThresholdExpression{Input: "A", Evaluator: EvaluatorWithinRange, Params: []float64{1}},
+		"no value in threshold":    ThresholdExpression{Input: "A", Evaluator: EvaluatorNoValue},
+		"no classic conditions":    ClassicConditionsExpression{},
+		"unknown classic operator": ClassicConditionsExpression{Conditions: []ClassicCondition{{Query: "A", Reducer: "avg", Evaluator: EvaluatorNoValue, Operator: "xor"}}},
+	}
+
+	for name, model := range cases {
+		if err := model.Validate(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestMathExpressionInputs(t *testing.T) {
+	inputs := MathExpression{Expression: "($A + ${B C}) / $A > $cpu_1"}.Inputs()
+	if strings.Join(inputs, ",") != "A,B C,cpu_1" {
+		t.Errorf("Unexpected inputs: %v", inputs)
+	}
+}
+
+func TestAlertRuleValidateQueries(t *testing.T) {
+	rule := AlertRule{
+		Title:     "High CPU",
+		Condition: "C",
+		Data: []*AlertQuery{
+			NewAlertQuery("A", "prom", RelativeTimeRange{From: 10 * time.Minute}, PrometheusAlertQueryModel{Expr: "cpu"}),
+			NewExpressionQuery("B", ReduceExpression{Input: "A", Reducer: ExpressionReducerMean}),
+			NewExpressionQuery("C", ThresholdExpression{Input: "B", Evaluator: EvaluatorGreaterThan, Params: []float64{0.9}}),
+		},
+	}
+	if err := rule.ValidateQueries(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(rule.Data[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"datasourceUid":"__expr__","model":{"type":"reduce","datasource":{"type":"__expr__","uid":"__expr__"},"expression":"A","reducer":"mean"},"refId":"B","relativeTimeRange":{"from":0,"to":0}}`
+	if string(data) != expected {
+		t.Errorf("Unexpected query JSON:\n%s", data)
+	}
+
+	rule.Data[2].Model = ThresholdExpression{Input: "D", Evaluator: EvaluatorGreaterThan, Params: []float64{0.9}}
+	if err := rule.ValidateQueries(); err == nil || !strings.Contains(err.Error(), "unknown input D") {
+		t.Errorf("Expected an unknown input error, got %v", err)
+	}
+
+	rule.Data[2].Model = MathExpression{Expression: "$C > 1"}
+	if err := rule.ValidateQueries(); err == nil || !strings.Contains(err.Error(), "refers to itself") {
+		t.Errorf("Expected a self reference error, got %v", err)
+	}
+
+	rule.Data[2].Model = MathExpression{Expression: "$B > 1"}
+	rule.Condition = "D"
+	if err := rule.ValidateQueries(); err == nil {
+		t.Error("Expected an error for an unknown condition")
+	}
+
+	rule.Condition = "C"
+	rule.Data[2].RefID = "B"
+	if err := rule.ValidateQueries(); err == nil || !strings.Contains(err.Error(), "duplicate ref ID") {
+		t.Errorf("Expected a duplicate ref ID error, got %v", err)
+	}
+}
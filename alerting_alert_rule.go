@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"sort"
 	"time"
 )
 
@@ -62,6 +64,123 @@ type RelativeTimeRange struct {
 	To   time.Duration `json:"to"`
 }
 
+// AlertingExportFormat is the format of exported alerting resources.
+type AlertingExportFormat string
+
+const (
+	ExportFormatYAML AlertingExportFormat = "yaml"
+	ExportFormatJSON AlertingExportFormat = "json"
+	ExportFormatHCL  AlertingExportFormat = "hcl"
+)
+
+// AlertRuleExportOptions selects the alert rules to export. Every rule is exported if no filter is set.
+type AlertRuleExportOptions struct {
+	Format     AlertingExportFormat
+	FolderUIDs []string
+	// Group requires a single folder UID.
+	Group   string
+	RuleUID string
+}
+
+// AlertRules fetches all alert rules.
+func (c *Client) AlertRules() ([]AlertRule, error) {
+	result := make([]AlertRule, 0)
+	err := c.request("GET", "/api/v1/provisioning/alert-rules", nil, nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// AlertRuleGroups fetches the alert rule groups of the folder whose UID it's passed, or of every folder if it's empty.
+// Groups are sorted by folder UID and title.
+func (c *Client) AlertRuleGroups(folderUID string) ([]RuleGroup, error) {
+	rules, err := c.AlertRules()
+	if err != nil {
+		return nil, err
+	}
+
+	type groupKey struct {
+		folderUID string
+		title     string
+	}
+	keys := make([]groupKey, 0)
+	seen := make(map[groupKey]bool)
+	for _, rule := range rules {
+		key := groupKey{folderUID: rule.FolderUID, title: rule.RuleGroup}
+		if (folderUID != "" && key.folderUID != folderUID) || seen[key] {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].folderUID != keys[j].folderUID {
+			return keys[i].folderUID < keys[j].folderUID
+		}
+		return keys[i].title < keys[j].title
+	})
+
+	// Rules don't carry the evaluation interval of their group, which needs to be fetched separately.
+	groups := make([]RuleGroup, 0, len(keys))
+	for _, key := range keys {
+		group, err := c.AlertRuleGroup(key.folderUID, key.title)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+
+	return groups, nil
+}
+
+// ExportAlertRules exports the alert rules selected by the options, in the provisioning file format.
+func (c *Client) ExportAlertRules(opts AlertRuleExportOptions) ([]byte, error) {
+	if opts.Group != "" && len(opts.FolderUIDs) != 1 {
+		return nil, fmt.Errorf("exporting a rule group requires exactly one folder UID")
+	}
+
+	query := alertingExportQuery(opts.Format)
+	for _, uid := range opts.FolderUIDs {
+		query.Add("folderUid", uid)
+	}
+	if opts.Group != "" {
+		query.Set("group", opts.Group)
+	}
+	if opts.RuleUID != "" {
+		query.Set("ruleUid", opts.RuleUID)
+	}
+
+	var result []byte
+	err := c.request("GET", "/api/v1/provisioning/alert-rules/export", query, nil, &result)
+	return result, err
+}
+
+// ExportAlertRule exports a single alert rule, identified by its UID, in the provisioning file format.
+func (c *Client) ExportAlertRule(uid string, format AlertingExportFormat) ([]byte, error) {
+	path := fmt.Sprintf("/api/v1/provisioning/alert-rules/%s/export", uid)
+	var result []byte
+	err := c.request("GET", path, alertingExportQuery(format), nil, &result)
+	return result, err
+}
+
+// ExportAlertRuleGroup exports a group of alert rules, identified by its name and the UID of its folder,
+// in the provisioning file format.
+func (c *Client) ExportAlertRuleGroup(folderUID string, name string, format AlertingExportFormat) ([]byte, error) {
+	path := fmt.Sprintf("/api/v1/provisioning/folder/%s/rule-groups/%s/export", folderUID, name)
+	var result []byte
+	err := c.request("GET", path, alertingExportQuery(format), nil, &result)
+	return result, err
+}
+
+func alertingExportQuery(format AlertingExportFormat) url.Values {
+	query := url.Values{}
+	if format != "" {
+		query.Set("format", string(format))
+	}
+	return query
+}
+
 // AlertRule fetches a single alert rule, identified by its UID.
 func (c *Client) AlertRule(uid string) (AlertRule, error) {
 	path := fmt.Sprintf("/api/v1/provisioning/alert-rules/%s", uid)
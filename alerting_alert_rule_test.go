@@ -120,6 +120,93 @@ func TestAlertRules(t *testing.T) {
 	})
 }
 
+func TestAlertRulesList(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, listAlertRulesJSON}})
+	defer server.Close()
+
+	rules, err := client.AlertRules()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(pretty.PrettyFormat(rules))
+
+	if len(rules) != 3 || rules[0].UID != "rule1" || rules[2].RuleGroup != "group_b" {
+		t.Error("Not correctly parsing returned alert rules.")
+	}
+	if path := server.receivedRequests[0].path; path != "/api/v1/provisioning/alert-rules" {
+		t.Errorf("Unexpected request path: %s", path)
+	}
+}
+
+func TestAlertRuleGroups(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{
+		{200, listAlertRulesJSON},
+		{200, `{"title": "group_a", "folderUid": "folder1", "interval": 60, "rules": []}`},
+		{200, `{"title": "group_b", "folderUid": "folder1", "interval": 120, "rules": []}`},
+	})
+	defer server.Close()
+
+	groups, err := client.AlertRuleGroups("folder1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(groups) != 2 || groups[0].Title != "group_a" || groups[1].Interval != 120 {
+		t.Errorf("Unexpected rule groups: %v", groups)
+	}
+	for i, group := range []string{"group_a", "group_b"} {
+		expected := "/api/v1/provisioning/folder/folder1/rule-groups/" + group
+		if path := server.receivedRequests[i+1].path; path != expected {
+			t.Errorf("Unexpected request path: %s", path)
+		}
+	}
+}
+
+func TestExportAlertRules(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{
+		{200, exportAlertRulesYAML},
+		{200, exportAlertRulesYAML},
+		{200, exportAlertRulesYAML},
+	})
+	defer server.Close()
+
+	export, err := client.ExportAlertRules(AlertRuleExportOptions{
+		Format:     ExportFormatYAML,
+		FolderUIDs: []string{"folder1"},
+		Group:      "group_a",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(export) != exportAlertRulesYAML {
+		t.Errorf("Unexpected export: %s", export)
+	}
+	req := server.receivedRequests[0]
+	if req.path != "/api/v1/provisioning/alert-rules/export" || req.query.Encode() != "folderUid=folder1&format=yaml&group=group_a" {
+		t.Errorf("Unexpected request: %s?%s", req.path, req.query.Encode())
+	}
+
+	if _, err := client.ExportAlertRule("rule1", ExportFormatHCL); err != nil {
+		t.Fatal(err)
+	}
+	req = server.receivedRequests[1]
+	if req.path != "/api/v1/provisioning/alert-rules/rule1/export" || req.query.Get("format") != "hcl" {
+		t.Errorf("Unexpected request: %s?%s", req.path, req.query.Encode())
+	}
+
+	if _, err := client.ExportAlertRuleGroup("folder1", "group_a", ExportFormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	req = server.receivedRequests[2]
+	if req.path != "/api/v1/provisioning/folder/folder1/rule-groups/group_a/export" || req.query.Get("format") != "json" {
+		t.Errorf("Unexpected request: %s?%s", req.path, req.query.Encode())
+	}
+
+	if _, err := client.ExportAlertRules(AlertRuleExportOptions{Group: "group_a"}); err == nil {
+		t.Error("Expected an error when exporting a group without folder")
+	}
+}
+
 func createAlertRuleGroup() RuleGroup {
 	return RuleGroup{
 		Title:     "eval_group_1",
@@ -155,6 +242,23 @@ func createAlertQueries() []*AlertQuery {
 	return alertQueries
 }
 
+const listAlertRulesJSON = `
+[
+	{"uid": "rule1", "folderUID": "folder1", "ruleGroup": "group_b", "title": "Rule 1", "condition": "A", "data": [], "for": "1m"},
+	{"uid": "rule2", "folderUID": "folder1", "ruleGroup": "group_a", "title": "Rule 2", "condition": "A", "data": [], "for": "1m"},
+	{"uid": "rule3", "folderUID": "folder2", "ruleGroup": "group_b", "title": "Rule 3", "condition": "A", "data": [], "for": "5m"}
+]
+`
+
+const exportAlertRulesYAML = `apiVersion: 1
+groups:
+    - orgId: 1
+      name: group_a
+      folder: Folder 1
+      interval: 1m
+      rules: []
+`
+
 const writeAlertRuleJSON = `
 	{
 	"conditions": "A",
@@ -0,0 +1,307 @@
+package gapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// GrafanaAlertmanagerUID identifies the Alertmanager built into Grafana. External Alertmanagers are identified
+// by the UID of their data source.
+const GrafanaAlertmanagerUID = "grafana"
+
+// Silence states.
+const (
+	SilenceStateActive  = "active"
+	SilenceStatePending = "pending"
+	SilenceStateExpired = "expired"
+)
+
+// Silence represents an Alertmanager silence, muting the alerts matching all of its matchers between
+// StartsAt and EndsAt.
+type Silence struct {
+	ID        string
+	Matchers  Matchers
+	StartsAt  time.Time
+	EndsAt    time.Time
+	CreatedBy string
+	Comment   string
+	// Status is derived by the Alertmanager from StartsAt and EndsAt, and UpdatedAt is set each time the silence is saved.
+	// Both are filled in when fetching silences and never sent when creating or updating one.
+	Status    SilenceStatus
+	UpdatedAt time.Time
+}
+
+// SilenceStatus represents the status of an Alertmanager silence.
+type SilenceStatus struct {
+	State string `json:"state"`
+}
+
+// silenceMatcher represents a matcher in the format of the Alertmanager API.
+type silenceMatcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+	// IsEqual is missing in the responses of older Alertmanagers, where it's implicitly true.
+	IsEqual *bool `json:"isEqual,omitempty"`
+}
+
+type silenceJSON struct {
+	ID        string           `json:"id,omitempty"`
+	Matchers  []silenceMatcher `json:"matchers"`
+	StartsAt  time.Time        `json:"startsAt"`
+	EndsAt    time.Time        `json:"endsAt"`
+	CreatedBy string           `json:"createdBy"`
+	Comment   string           `json:"comment"`
+	Status    *SilenceStatus   `json:"status,omitempty"`
+	UpdatedAt *time.Time       `json:"updatedAt,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface for Silence.
+func (s Silence) MarshalJSON() ([]byte, error) {
+	matchers := make([]silenceMatcher, len(s.Matchers))
+	for i, m := range s.Matchers {
+		isEqual := m.Type == MatchEqual || m.Type == MatchRegexp
+		matchers[i] = silenceMatcher{
+			Name:    m.Name,
+			Value:   m.Value,
+			IsRegex: m.Type == MatchRegexp || m.Type == MatchNotRegexp,
+			IsEqual: &isEqual,
+		}
+	}
+	return json.Marshal(silenceJSON{
+		ID:        s.ID,
+		Matchers:  matchers,
+		StartsAt:  s.StartsAt,
+		EndsAt:    s.EndsAt,
+		CreatedBy: s.CreatedBy,
+		Comment:   s.Comment,
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for Silence.
+func (s *Silence) UnmarshalJSON(data []byte) error {
+	raw := silenceJSON{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	matchers := make(Matchers, len(raw.Matchers))
+	for i, m := range raw.Matchers {
+		isEqual := m.IsEqual == nil || *m.IsEqual
+		matchType := MatchEqual
+		switch {
+		case m.IsRegex && isEqual:
+			matchType = MatchRegexp
+		case m.IsRegex:
+			matchType = MatchNotRegexp
+		case !isEqual:
+			matchType = MatchNotEqual
+		}
+		matchers[i] = Matcher{Type: matchType, Name: m.Name, Value: m.Value}
+	}
+
+	*s = Silence{
+		ID:        raw.ID,
+		Matchers:  matchers,
+		StartsAt:  raw.StartsAt,
+		EndsAt:    raw.EndsAt,
+		CreatedBy: raw.CreatedBy,
+		Comment:   raw.Comment,
+	}
+	if raw.Status != nil {
+		s.Status = *raw.Status
+	}
+	if raw.UpdatedAt != nil {
+		s.UpdatedAt = *raw.UpdatedAt
+	}
+	return nil
+}
+
+// AlertmanagerAlert represents an alert as known by an Alertmanager.
+type AlertmanagerAlert struct {
+	Labels       map[string]string       `json:"labels"`
+	Annotations  map[string]string       `json:"annotations"`
+	StartsAt     time.Time               `json:"startsAt"`
+	EndsAt       time.Time               `json:"endsAt"`
+	UpdatedAt    time.Time               `json:"updatedAt"`
+	GeneratorURL string                  `json:"generatorURL"`
+	Fingerprint  string                  `json:"fingerprint"`
+	Receivers    []AlertmanagerReceiver  `json:"receivers"`
+	Status       AlertmanagerAlertStatus `json:"status"`
+}
+
+// AlertmanagerAlertStatus represents the status of an alert within an Alertmanager.
+type AlertmanagerAlertStatus struct {
+	// State is either unprocessed, active or suppressed.
+	State       string   `json:"state"`
+	SilencedBy  []string `json:"silencedBy"`
+	InhibitedBy []string `json:"inhibitedBy"`
+}
+
+// AlertmanagerReceiver represents the receiver an alert is routed to.
+type AlertmanagerReceiver struct {
+	Name string `json:"name"`
+}
+
+// AlertmanagerAlertGroup represents alerts grouped together by the notification policies.
+type AlertmanagerAlertGroup struct {
+	Labels   map[string]string    `json:"labels"`
+	Receiver AlertmanagerReceiver `json:"receiver"`
+	Alerts   []AlertmanagerAlert  `json:"alerts"`
+}
+
+// AlertmanagerAlertFilter filters the alerts returned by an Alertmanager. Every alert is returned by default.
+type AlertmanagerAlertFilter struct {
+	// Matchers select the alerts whose labels match all of them.
+	Matchers Matchers
+	// Receiver is a regular expression the receiver of the alerts must match.
+	Receiver         string
+	ExcludeActive    bool
+	ExcludeSilenced  bool
+	ExcludeInhibited bool
+}
+
+func (f AlertmanagerAlertFilter) query() url.Values {
+	query := matchersFilterQuery(f.Matchers)
+	if f.Receiver != "" {
+		query.Set("receiver", f.Receiver)
+	}
+	if f.ExcludeActive {
+		query.Set("active", strconv.FormatBool(false))
+	}
+	if f.ExcludeSilenced {
+		query.Set("silenced", strconv.FormatBool(false))
+	}
+	if f.ExcludeInhibited {
+		query.Set("inhibited", strconv.FormatBool(false))
+	}
+	return query
+}
+
+// AlertmanagerStatus represents the status of an Alertmanager.
+type AlertmanagerStatus struct {
+	Cluster struct {
+		Status string `json:"status"`
+		Peers  []struct {
+			Name    string `json:"name"`
+			Address string `json:"address"`
+		} `json:"peers"`
+	} `json:"cluster"`
+	// Config holds the configuration of the Alertmanager, whose format depends on its implementation.
+	Config      json.RawMessage   `json:"config"`
+	Uptime      time.Time         `json:"uptime"`
+	VersionInfo map[string]string `json:"versionInfo"`
+}
+
+// Silences fetches the silences of the Alertmanager whose UID it's passed, optionally only the ones
+// whose matchers include all of the filter matchers.
+func (c *Client) Silences(alertmanagerUID string, filter Matchers) ([]Silence, error) {
+	silences := make([]Silence, 0)
+	err := c.request("GET", alertmanagerPath(alertmanagerUID, "silences"), matchersFilterQuery(filter), nil, &silences)
+	if err != nil {
+		return nil, err
+	}
+	return silences, nil
+}
+
+// Silence fetches a single silence of the Alertmanager whose UID it's passed, identified by its ID.
+func (c *Client) Silence(alertmanagerUID, id string) (*Silence, error) {
+	silence := &Silence{}
+	err := c.request("GET", alertmanagerPath(alertmanagerUID, "silence/"+id), nil, nil, silence)
+	if err != nil {
+		return nil, err
+	}
+	return silence, nil
+}
+
+// NewSilence creates a new silence in the Alertmanager whose UID it's passed and returns its ID.
+func (c *Client) NewSilence(alertmanagerUID string, s *Silence) (string, error) {
+	if s.ID != "" {
+		return "", fmt.Errorf("new silences can't have an ID, use UpdateSilence instead")
+	}
+	return c.postSilence(alertmanagerUID, s)
+}
+
+// UpdateSilence updates a silence of the Alertmanager whose UID it's passed, identified by its ID, and returns
+// its ID. The Alertmanager may create a new silence with a different ID instead, e.g. if it's already expired.
+func (c *Client) UpdateSilence(alertmanagerUID string, s *Silence) (string, error) {
+	if s.ID == "" {
+		return "", fmt.Errorf("the silence to update must have an ID")
+	}
+	return c.postSilence(alertmanagerUID, s)
+}
+
+// DeleteSilence expires a silence of the Alertmanager whose UID it's passed, identified by its ID.
+func (c *Client) DeleteSilence(alertmanagerUID, id string) error {
+	return c.request("DELETE", alertmanagerPath(alertmanagerUID, "silence/"+id), nil, nil, nil)
+}
+
+func (c *Client) postSilence(alertmanagerUID string, s *Silence) (string, error) {
+	if len(s.Matchers) == 0 {
+		return "", fmt.Errorf("a silence requires at least one matcher")
+	}
+	if !s.EndsAt.After(s.StartsAt) {
+		return "", fmt.Errorf("a silence must end after it starts")
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+
+	result := struct {
+		SilenceID string `json:"silenceID"`
+	}{}
+	err = c.request("POST", alertmanagerPath(alertmanagerUID, "silences"), nil, bytes.NewBuffer(data), &result)
+	if err != nil {
+		return "", err
+	}
+	return result.SilenceID, nil
+}
+
+// AlertmanagerAlerts fetches the alerts of the Alertmanager whose UID it's passed, selected by the filter.
+func (c *Client) AlertmanagerAlerts(alertmanagerUID string, filter AlertmanagerAlertFilter) ([]AlertmanagerAlert, error) {
+	alerts := make([]AlertmanagerAlert, 0)
+	err := c.request("GET", alertmanagerPath(alertmanagerUID, "alerts"), filter.query(), nil, &alerts)
+	if err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+// AlertmanagerAlertGroups fetches the alert groups of the Alertmanager whose UID it's passed,
+// with their alerts selected by the filter.
+func (c *Client) AlertmanagerAlertGroups(alertmanagerUID string, filter AlertmanagerAlertFilter) ([]AlertmanagerAlertGroup, error) {
+	groups := make([]AlertmanagerAlertGroup, 0)
+	err := c.request("GET", alertmanagerPath(alertmanagerUID, "alerts/groups"), filter.query(), nil, &groups)
+	if err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// AlertmanagerStatus fetches the status of the Alertmanager whose UID it's passed.
+func (c *Client) AlertmanagerStatus(alertmanagerUID string) (*AlertmanagerStatus, error) {
+	status := &AlertmanagerStatus{}
+	err := c.request("GET", alertmanagerPath(alertmanagerUID, "status"), nil, nil, status)
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+func alertmanagerPath(alertmanagerUID, path string) string {
+	return fmt.Sprintf("/api/alertmanager/%s/api/v2/%s", alertmanagerUID, path)
+}
+
+func matchersFilterQuery(matchers Matchers) url.Values {
+	query := url.Values{}
+	for _, m := range matchers {
+		query.Add("filter", m.String())
+	}
+	return query
+}
@@ -0,0 +1,229 @@
+package gapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gobs/pretty"
+)
+
+const (
+	getSilenceJSON = `
+{
+	"id": "6f3b3b2c-1a4e-4c4f-9a0e-1c2d3e4f5a6b",
+	"matchers": [
+		{"name": "team", "value": "infra", "isRegex": false, "isEqual": true},
+		{"name": "severity", "value": "info|debug", "isRegex": true, "isEqual": false},
+		{"name": "env", "value": "prod", "isRegex": false}
+	],
+	"startsAt": "2022-09-01T10:00:00Z",
+	"endsAt": "2022-09-01T12:00:00Z",
+	"createdBy": "deploy-bot",
+	"comment": "Deploying",
+	"status": {"state": "expired"},
+	"updatedAt": "2022-09-01T12:00:00Z"
+}`
+	getSilencesJSON   = "[" + getSilenceJSON + "]"
+	createSilenceJSON = `{"silenceID": "9c1b2a3d-4e5f-6a7b-8c9d-0e1f2a3b4c5d"}`
+
+	getAlertmanagerAlertsJSON = `
+[
+	{
+		"labels": {"alertname": "HighCPU", "team": "infra"},
+		"annotations": {"summary": "CPU is high"},
+		"startsAt": "2022-09-01T10:00:00Z",
+		"endsAt": "2022-09-01T10:05:00Z",
+		"updatedAt": "2022-09-01T10:01:00Z",
+		"generatorURL": "http://localhost:3000/alerting/grafana/abc/view",
+		"fingerprint": "3c5f1b2d4e6a7b8c",
+		"receivers": [{"name": "infra-slack"}],
+		"status": {"state": "suppressed", "silencedBy": ["6f3b3b2c"], "inhibitedBy": []}
+	}
+]
+`
+
+	getAlertmanagerAlertGroupsJSON = `
+[
+	{
+		"labels": {"alertname": "HighCPU"},
+		"receiver": {"name": "infra-slack"},
+		"alerts": [{"labels": {"alertname": "HighCPU"}, "status": {"state": "active"}}]
+	}
+]
+`
+
+	getAlertmanagerStatusJSON = `
+{
+	"cluster": {"status": "ready", "peers": [{"name": "01G", "address": "10.0.0.1:9094"}]},
+	"config": {"route": {"receiver": "grafana-default-email"}},
+	"uptime": "2022-09-01T08:00:00Z",
+	"versionInfo": {"version": "0.24.0"}
+}
+`
+)
+
+func TestSilences(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, getSilencesJSON}})
+	defer server.Close()
+
+	silences, err := client.Silences(GrafanaAlertmanagerUID, Matchers{{Type: MatchEqual, Name: "team", Value: "infra"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(pretty.PrettyFormat(silences))
+
+	req := server.receivedRequests[0]
+	if req.path != "/api/alertmanager/grafana/api/v2/silences" || req.query.Get("filter") != `team="infra"` {
+		t.Errorf("Unexpected request: %s?%s", req.path, req.query.Encode())
+	}
+
+	if len(silences) != 1 {
+		t.Fatalf("Expected 1 silence, got %d", len(silences))
+	}
+	silence := silences[0]
+	if silence.Status.State != SilenceStateExpired || silence.CreatedBy != "deploy-bot" || silence.UpdatedAt.IsZero() {
+		t.Error("Not correctly parsing returned silence.")
+	}
+	expectedTypes := []MatchType{MatchEqual, MatchNotRegexp, MatchEqual}
+	for i, m := range silence.Matchers {
+		if m.Type != expectedTypes[i] {
+			t.Errorf("Unexpected type for matcher %s: %s", m.Name, m.Type)
+		}
+	}
+}
+
+func TestSilence(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, getSilenceJSON}})
+	defer server.Close()
+
+	silence, err := client.Silence("alertmanager-ds", "6f3b3b2c-1a4e-4c4f-9a0e-1c2d3e4f5a6b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if path := server.receivedRequests[0].path; path != "/api/alertmanager/alertmanager-ds/api/v2/silence/6f3b3b2c-1a4e-4c4f-9a0e-1c2d3e4f5a6b" {
+		t.Errorf("Unexpected request path: %s", path)
+	}
+	if silence.Comment != "Deploying" || len(silence.Matchers) != 3 {
+		t.Error("Not correctly parsing returned silence.")
+	}
+}
+
+func TestNewSilence(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, createSilenceJSON}})
+	defer server.Close()
+
+	start := time.Date(2022, 9, 1, 10, 0, 0, 0, time.UTC)
+	silence := &Silence{
+		Matchers: Matchers{
+			{Type: MatchRegexp, Name: "team", Value: "infra|platform"},
+			{Type: MatchNotEqual, Name: "severity", Value: "critical"},
+		},
+		StartsAt:  start,
+		EndsAt:    start.Add(time.Hour),
+		CreatedBy: "deploy-bot",
+		Comment:   "Deploying",
+	}
+	id, err := client.NewSilence(GrafanaAlertmanagerUID, silence)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "9c1b2a3d-4e5f-6a7b-8c9d-0e1f2a3b4c5d" {
+		t.Errorf("Unexpected silence ID: %s", id)
+	}
+
+	req := server.receivedRequests[0]
+	expected := `{"matchers":[{"name":"team","value":"infra|platform","isRegex":true,"isEqual":true},{"name":"severity","value":"critical","isRegex":false,"isEqual":false}],"startsAt":"2022-09-01T10:00:00Z","endsAt":"2022-09-01T11:00:00Z","createdBy":"deploy-bot","comment":"Deploying"}`
+	if req.method != "POST" || req.path != "/api/alertmanager/grafana/api/v2/silences" || req.body != expected {
+		t.Errorf("Unexpected request: %s %s %s", req.method, req.path, req.body)
+	}
+}
+
+func TestNewSilenceValidation(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{})
+	defer server.Close()
+
+	start := time.Now()
+	if _, err := client.NewSilence(GrafanaAlertmanagerUID, &Silence{StartsAt: start, EndsAt: start.Add(time.Hour)}); err == nil {
+		t.Error("Expected an error for a silence without matchers")
+	}
+	matchers := Matchers{{Type: MatchEqual, Name: "team", Value: "infra"}}
+	if _, err := client.NewSilence(GrafanaAlertmanagerUID, &Silence{Matchers: matchers, StartsAt: start, EndsAt: start}); err == nil {
+		t.Error("Expected an error for a silence ending when it starts")
+	}
+	if _, err := client.UpdateSilence(GrafanaAlertmanagerUID, &Silence{Matchers: matchers, StartsAt: start, EndsAt: start.Add(time.Hour)}); err == nil {
+		t.Error("Expected an error when updating a silence without ID")
+	}
+}
+
+func TestDeleteSilence(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, ""}})
+	defer server.Close()
+
+	if err := client.DeleteSilence(GrafanaAlertmanagerUID, "abc"); err != nil {
+		t.Fatal(err)
+	}
+	req := server.receivedRequests[0]
+	if req.method != "DELETE" || req.path != "/api/alertmanager/grafana/api/v2/silence/abc" {
+		t.Errorf("Unexpected request: %s %s", req.method, req.path)
+	}
+}
+
+func TestAlertmanagerAlerts(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{
+		{200, getAlertmanagerAlertsJSON},
+		{200, getAlertmanagerAlertGroupsJSON},
+	})
+	defer server.Close()
+
+	filter := AlertmanagerAlertFilter{
+		Matchers:        Matchers{{Type: MatchEqual, Name: "team", Value: "infra"}, {Type: MatchRegexp, Name: "severity", Value: "crit.*"}},
+		Receiver:        "infra-.*",
+		ExcludeSilenced: true,
+	}
+	alerts, err := client.AlertmanagerAlerts(GrafanaAlertmanagerUID, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(pretty.PrettyFormat(alerts))
+
+	req := server.receivedRequests[0]
+	if req.path != "/api/alertmanager/grafana/api/v2/alerts" {
+		t.Errorf("Unexpected request path: %s", req.path)
+	}
+	if filters := req.query["filter"]; len(filters) != 2 || filters[1] != `severity=~"crit.*"` {
+		t.Errorf("Unexpected filters: %v", filters)
+	}
+	if req.query.Get("receiver") != "infra-.*" || req.query.Get("silenced") != "false" || req.query.Get("active") != "" {
+		t.Errorf("Unexpected query: %s", req.query.Encode())
+	}
+	if len(alerts) != 1 || alerts[0].Receivers[0].Name != "infra-slack" || alerts[0].Status.SilencedBy[0] != "6f3b3b2c" {
+		t.Error("Not correctly parsing returned alerts.")
+	}
+
+	groups, err := client.AlertmanagerAlertGroups(GrafanaAlertmanagerUID, AlertmanagerAlertFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req = server.receivedRequests[1]
+	if req.path != "/api/alertmanager/grafana/api/v2/alerts/groups" || len(req.query) != 0 {
+		t.Errorf("Unexpected request: %s?%s", req.path, req.query.Encode())
+	}
+	if len(groups) != 1 || groups[0].Receiver.Name != "infra-slack" || len(groups[0].Alerts) != 1 {
+		t.Error("Not correctly parsing returned alert groups.")
+	}
+}
+
+func TestAlertmanagerStatus(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, getAlertmanagerStatusJSON}})
+	defer server.Close()
+
+	status, err := client.AlertmanagerStatus(GrafanaAlertmanagerUID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if status.Cluster.Status != "ready" || len(status.Cluster.Peers) != 1 || status.VersionInfo["version"] != "0.24.0" || len(status.Config) == 0 {
+		t.Error("Not correctly parsing returned status.")
+	}
+}
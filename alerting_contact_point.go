@@ -40,6 +40,7 @@ func (c *Client) ContactPointsByName(name string) ([]ContactPoint, error) {
 }
 
 // ContactPoint fetches a single contact point, identified by its UID.
+// Grafana has no endpoint for a single contact point, so all of them are fetched: use Receiver when its name is known.
 func (c *Client) ContactPoint(uid string) (ContactPoint, error) {
 	ps, err := c.ContactPoints()
 	if err != nil {
@@ -0,0 +1,434 @@
+package gapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Contact point types supported by the typed contact point settings.
+const (
+	ContactPointTypeSlack      = "slack"
+	ContactPointTypePagerDuty  = "pagerduty"
+	ContactPointTypeEmail      = "email"
+	ContactPointTypeWebhook    = "webhook"
+	ContactPointTypeOpsGenie   = "opsgenie"
+	ContactPointTypeTeams      = "teams"
+	ContactPointTypeTelegram   = "telegram"
+	ContactPointTypeDiscord    = "discord"
+	ContactPointTypeGoogleChat = "googlechat"
+)
+
+// RedactedValue replaces the secure settings of contact points read from Grafana.
+// Sending it back on update keeps the stored value.
+const RedactedValue = "[REDACTED]"
+
+// ContactPointSettings is implemented by the typed settings of each supported contact point type.
+// They marshal into the `settings` property of a ContactPoint.
+type ContactPointSettings interface {
+	// ContactPointType returns the type of the contact points the settings apply to.
+	ContactPointType() string
+	// Validate returns an error if a required setting is missing or invalid.
+	Validate() error
+	// SettingsMap returns the settings, secure ones included.
+	SettingsMap() (map[string]interface{}, error)
+	// SecureFields returns the names of the settings Grafana stores encrypted and redacts when read.
+	SecureFields() []string
+}
+
+// SetSettings validates the typed settings and sets the type and settings of the contact point from them.
+func (p *ContactPoint) SetSettings(s ContactPointSettings) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+
+	settings, err := s.SettingsMap()
+	if err != nil {
+		return err
+	}
+
+	p.Type = s.ContactPointType()
+	p.Settings = settings
+	return nil
+}
+
+// TypedSettings decodes the settings of the contact point into the typed settings matching its type.
+// Secure settings read from Grafana hold RedactedValue.
+func (p *ContactPoint) TypedSettings() (ContactPointSettings, error) {
+	var settings ContactPointSettings
+	switch p.Type {
+	case ContactPointTypeSlack:
+		settings = &SlackSettings{}
+	case ContactPointTypePagerDuty:
+		settings = &PagerDutySettings{}
+	case ContactPointTypeEmail:
+		settings = &EmailSettings{}
+	case ContactPointTypeWebhook:
+		settings = &WebhookSettings{}
+	case ContactPointTypeOpsGenie:
+		settings = &OpsGenieSettings{}
+	case ContactPointTypeTeams:
+		settings = &TeamsSettings{}
+	case ContactPointTypeTelegram:
+		settings = &TelegramSettings{}
+	case ContactPointTypeDiscord:
+		settings = &DiscordSettings{}
+	case ContactPointTypeGoogleChat:
+		settings = &GoogleChatSettings{}
+	default:
+		return nil, fmt.Errorf("no typed settings for contact point type %q", p.Type)
+	}
+
+	data, err := json.Marshal(p.Settings)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, settings); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// RedactedFields returns the names of the settings of the contact point redacted by Grafana.
+func (p *ContactPoint) RedactedFields() []string {
+	fields := make([]string, 0)
+	for name, value := range p.Settings {
+		if value == RedactedValue {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+func validateRequired(setting, value string) error {
+	if value == "" {
+		return fmt.Errorf("%s is required", setting)
+	}
+	return nil
+}
+
+// SlackSettings are the settings of Slack contact points, which either use an incoming webhook URL,
+// or a bot token along with a recipient.
+type SlackSettings struct {
+	URL            string `json:"url,omitempty"`
+	Token          string `json:"token,omitempty"`
+	Recipient      string `json:"recipient,omitempty"`
+	Username       string `json:"username,omitempty"`
+	IconEmoji      string `json:"icon_emoji,omitempty"`
+	IconURL        string `json:"icon_url,omitempty"`
+	MentionChannel string `json:"mentionChannel,omitempty"`
+	MentionUsers   string `json:"mentionUsers,omitempty"`
+	MentionGroups  string `json:"mentionGroups,omitempty"`
+	EndpointURL    string `json:"endpointUrl,omitempty"`
+	Title          string `json:"title,omitempty"`
+	Text           string `json:"text,omitempty"`
+}
+
+func (s *SlackSettings) ContactPointType() string {
+	return ContactPointTypeSlack
+}
+
+func (s *SlackSettings) Validate() error {
+	if s.URL == "" && s.Token == "" {
+		return fmt.Errorf("either url or token is required")
+	}
+	if s.Token != "" && s.Recipient == "" {
+		return fmt.Errorf("recipient is required when using a token")
+	}
+	return validateOneOf("mentionChannel", s.MentionChannel, "here", "channel")
+}
+
+func (s *SlackSettings) SettingsMap() (map[string]interface{}, error) {
+	return structToMap(s)
+}
+
+func (s *SlackSettings) SecureFields() []string {
+	return []string{"url", "token"}
+}
+
+// PagerDutySettings are the settings of PagerDuty contact points.
+type PagerDutySettings struct {
+	IntegrationKey string            `json:"integrationKey"`
+	Severity       string            `json:"severity,omitempty"`
+	Class          string            `json:"class,omitempty"`
+	Component      string            `json:"component,omitempty"`
+	Group          string            `json:"group,omitempty"`
+	Summary        string            `json:"summary,omitempty"`
+	Source         string            `json:"source,omitempty"`
+	Client         string            `json:"client,omitempty"`
+	ClientURL      string            `json:"client_url,omitempty"`
+	Details        map[string]string `json:"details,omitempty"`
+}
+
+func (s *PagerDutySettings) ContactPointType() string {
+	return ContactPointTypePagerDuty
+}
+
+func (s *PagerDutySettings) Validate() error {
+	if err := validateRequired("integrationKey", s.IntegrationKey); err != nil {
+		return err
+	}
+	// The severity can also be a template, e.g. {{ .CommonLabels.severity }}.
+	if strings.Contains(s.Severity, "{{") {
+		return nil
+	}
+	return validateOneOf("severity", s.Severity, "critical", "error", "warning", "info")
+}
+
+func (s *PagerDutySettings) SettingsMap() (map[string]interface{}, error) {
+	return structToMap(s)
+}
+
+func (s *PagerDutySettings) SecureFields() []string {
+	return []string{"integrationKey"}
+}
+
+// EmailAddresses is a list of email addresses, stored by Grafana as a single string separated by semicolons.
+type EmailAddresses []string
+
+// MarshalJSON implements the json.Marshaler interface for EmailAddresses.
+func (a EmailAddresses) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strings.Join(a, ";"))
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for EmailAddresses.
+func (a *EmailAddresses) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	addresses := make(EmailAddresses, 0)
+	for _, address := range strings.FieldsFunc(s, func(r rune) bool { return r == ';' || r == ',' || r == '\n' }) {
+		if address = strings.TrimSpace(address); address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+	*a = addresses
+	return nil
+}
+
+// EmailSettings are the settings of email contact points.
+type EmailSettings struct {
+	Addresses EmailAddresses `json:"addresses"`
+	// SingleEmail sends a single email to all addresses instead of one per address.
+	SingleEmail bool   `json:"singleEmail,omitempty"`
+	Subject     string `json:"subject,omitempty"`
+	Message     string `json:"message,omitempty"`
+}
+
+func (s *EmailSettings) ContactPointType() string {
+	return ContactPointTypeEmail
+}
+
+func (s *EmailSettings) Validate() error {
+	if len(s.Addresses) == 0 {
+		return fmt.Errorf("at least one address is required")
+	}
+	for _, address := range s.Addresses {
+		if !strings.Contains(address, "@") {
+			return fmt.Errorf("invalid email address %q", address)
+		}
+	}
+	return nil
+}
+
+func (s *EmailSettings) SettingsMap() (map[string]interface{}, error) {
+	return structToMap(s)
+}
+
+func (s *EmailSettings) SecureFields() []string {
+	return []string{}
+}
+
+// WebhookSettings are the settings of webhook contact points. Basic authentication and authorization
+// credentials are mutually exclusive.
+type WebhookSettings struct {
+	URL                      string `json:"url"`
+	HTTPMethod               string `json:"httpMethod,omitempty"`
+	Username                 string `json:"username,omitempty"`
+	Password                 string `json:"password,omitempty"`
+	AuthorizationScheme      string `json:"authorization_scheme,omitempty"`
+	AuthorizationCredentials string `json:"authorization_credentials,omitempty"`
+	Title                    string `json:"title,omitempty"`
+	Message                  string `json:"message,omitempty"`
+}
+
+func (s *WebhookSettings) ContactPointType() string {
+	return ContactPointTypeWebhook
+}
+
+func (s *WebhookSettings) Validate() error {
+	if err := validateRequired("url", s.URL); err != nil {
+		return err
+	}
+	if (s.Username != "" || s.Password != "") && s.AuthorizationCredentials != "" {
+		return fmt.Errorf("basic authentication and authorization credentials can't be used together")
+	}
+	return validateOneOf("httpMethod", s.HTTPMethod, "POST", "PUT")
+}
+
+func (s *WebhookSettings) SettingsMap() (map[string]interface{}, error) {
+	return structToMap(s)
+}
+
+func (s *WebhookSettings) SecureFields() []string {
+	return []string{"password", "authorization_credentials"}
+}
+
+// OpsGenieResponder is a team, user, escalation or schedule notified by OpsGenie,
+// identified by either its ID, name or username.
+type OpsGenieResponder struct {
+	Type     string `json:"type"`
+	ID       string `json:"id,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+// OpsGenieSettings are the settings of OpsGenie contact points.
+type OpsGenieSettings struct {
+	APIKey           string              `json:"apiKey"`
+	APIURL           string              `json:"apiUrl,omitempty"`
+	Message          string              `json:"message,omitempty"`
+	Description      string              `json:"description,omitempty"`
+	AutoClose        bool                `json:"autoClose,omitempty"`
+	OverridePriority bool                `json:"overridePriority,omitempty"`
+	SendTagsAs       string              `json:"sendTagsAs,omitempty"`
+	Responders       []OpsGenieResponder `json:"responders,omitempty"`
+}
+
+func (s *OpsGenieSettings) ContactPointType() string {
+	return ContactPointTypeOpsGenie
+}
+
+func (s *OpsGenieSettings) Validate() error {
+	if err := validateRequired("apiKey", s.APIKey); err != nil {
+		return err
+	}
+	if err := validateOneOf("sendTagsAs", s.SendTagsAs, "tags", "details", "both"); err != nil {
+		return err
+	}
+	for _, r := range s.Responders {
+		if err := validateRequired("responder type", r.Type); err != nil {
+			return err
+		}
+		if err := validateOneOf("responder type", r.Type, "team", "teams", "user", "escalation", "schedule"); err != nil {
+			return err
+		}
+		if r.ID == "" && r.Name == "" && r.Username == "" {
+			return fmt.Errorf("%s responder requires an id, a name or a username", r.Type)
+		}
+	}
+	return nil
+}
+
+func (s *OpsGenieSettings) SettingsMap() (map[string]interface{}, error) {
+	return structToMap(s)
+}
+
+func (s *OpsGenieSettings) SecureFields() []string {
+	return []string{"apiKey"}
+}
+
+// TeamsSettings are the settings of Microsoft Teams contact points.
+type TeamsSettings struct {
+	URL          string `json:"url"`
+	Title        string `json:"title,omitempty"`
+	SectionTitle string `json:"sectiontitle,omitempty"`
+	Message      string `json:"message,omitempty"`
+}
+
+func (s *TeamsSettings) ContactPointType() string {
+	return ContactPointTypeTeams
+}
+
+func (s *TeamsSettings) Validate() error {
+	return validateRequired("url", s.URL)
+}
+
+func (s *TeamsSettings) SettingsMap() (map[string]interface{}, error) {
+	return structToMap(s)
+}
+
+func (s *TeamsSettings) SecureFields() []string {
+	return []string{"url"}
+}
+
+// TelegramSettings are the settings of Telegram contact points.
+type TelegramSettings struct {
+	BotToken             string `json:"bottoken"`
+	ChatID               string `json:"chatid"`
+	Message              string `json:"message,omitempty"`
+	ParseMode            string `json:"parse_mode,omitempty"`
+	DisableNotifications bool   `json:"disable_notifications,omitempty"`
+}
+
+func (s *TelegramSettings) ContactPointType() string {
+	return ContactPointTypeTelegram
+}
+
+func (s *TelegramSettings) Validate() error {
+	if err := validateRequired("bottoken", s.BotToken); err != nil {
+		return err
+	}
+	if err := validateRequired("chatid", s.ChatID); err != nil {
+		return err
+	}
+	return validateOneOf("parse_mode", s.ParseMode, "None", "Markdown", "MarkdownV2", "HTML")
+}
+
+func (s *TelegramSettings) SettingsMap() (map[string]interface{}, error) {
+	return structToMap(s)
+}
+
+func (s *TelegramSettings) SecureFields() []string {
+	return []string{"bottoken"}
+}
+
+// DiscordSettings are the settings of Discord contact points.
+type DiscordSettings struct {
+	URL                string `json:"url"`
+	Title              string `json:"title,omitempty"`
+	Message            string `json:"message,omitempty"`
+	AvatarURL          string `json:"avatar_url,omitempty"`
+	UseDiscordUsername bool   `json:"use_discord_username,omitempty"`
+}
+
+func (s *DiscordSettings) ContactPointType() string {
+	return ContactPointTypeDiscord
+}
+
+func (s *DiscordSettings) Validate() error {
+	return validateRequired("url", s.URL)
+}
+
+func (s *DiscordSettings) SettingsMap() (map[string]interface{}, error) {
+	return structToMap(s)
+}
+
+func (s *DiscordSettings) SecureFields() []string {
+	return []string{"url"}
+}
+
+// GoogleChatSettings are the settings of Google Chat contact points.
+type GoogleChatSettings struct {
+	URL     string `json:"url"`
+	Title   string `json:"title,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+func (s *GoogleChatSettings) ContactPointType() string {
+	return ContactPointTypeGoogleChat
+}
+
+func (s *GoogleChatSettings) Validate() error {
+	return validateRequired("url", s.URL)
+}
+
+func (s *GoogleChatSettings) SettingsMap() (map[string]interface{}, error) {
+	return structToMap(s)
+}
+
+func (s *GoogleChatSettings) SecureFields() []string {
+	return []string{"url"}
+}
@@ -0,0 +1,138 @@
+package gapi
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/gobs/pretty"
+)
+
+func TestContactPointSetSettings(t *testing.T) {
+	p := &ContactPoint{Name: "infra"}
+	settings := &SlackSettings{
+		Token:          "xoxb-secret",
+		Recipient:      "#infra",
+		MentionChannel: "here",
+	}
+
+	if err := p.SetSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(pretty.PrettyFormat(p))
+
+	expected := map[string]interface{}{
+		"token":          "xoxb-secret",
+		"recipient":      "#infra",
+		"mentionChannel": "here",
+	}
+	if p.Type != "slack" || !reflect.DeepEqual(p.Settings, expected) {
+		t.Errorf("Unexpected contact point: %s %v", p.Type, p.Settings)
+	}
+}
+
+func TestContactPointSetSettingsEmailAddresses(t *testing.T) {
+	p := &ContactPoint{Name: "ops"}
+	settings := &EmailSettings{Addresses: EmailAddresses{"a@example.com", "b@example.com"}, SingleEmail: true}
+
+	if err := p.SetSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+	if p.Settings["addresses"] != "a@example.com;b@example.com" || p.Settings["singleEmail"] != true {
+		t.Errorf("Unexpected settings: %v", p.Settings)
+	}
+}
+
+func TestContactPointSetSettingsInvalid(t *testing.T) {
+	cases := map[string]ContactPointSettings{
+		"slack without url or token":    &SlackSettings{},
+		"slack token without recipient": &SlackSettings{Token: "xoxb"},
+		"slack mention":                 &SlackSettings{URL: "https://hooks.slack.com/x", MentionChannel: "everyone"},
+		"pagerduty key":                 &PagerDutySettings{},
+		"pagerduty severity":            &PagerDutySettings{IntegrationKey: "key", Severity: "fatal"},
+		"email addresses":               &EmailSettings{},
+		"email address":                 &EmailSettings{Addresses: EmailAddresses{"ops"}},
+		"webhook url":                   &WebhookSettings{},
+		"webhook auth":                  &WebhookSettings{URL: "http://hook", Username: "u", AuthorizationCredentials: "token"},
+		"webhook method":                &WebhookSettings{URL: "http://hook", HTTPMethod: "GET"},
+		"opsgenie key":                  &OpsGenieSettings{},
+		"opsgenie responder":            &OpsGenieSettings{APIKey: "key", Responders: []OpsGenieResponder{{Type: "team"}}},
+		"teams url":                     &TeamsSettings{},
+		"telegram chat":                 &TelegramSettings{BotToken: "token"},
+		"discord url":                   &DiscordSettings{},
+		"googlechat url":                &GoogleChatSettings{},
+	}
+
+	for name, settings := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := &ContactPoint{}
+			if err := p.SetSettings(settings); err == nil {
+				t.Error("Expected a validation error.")
+			}
+		})
+	}
+
+	templated := &PagerDutySettings{IntegrationKey: "key", Severity: "{{ .CommonLabels.severity }}"}
+	if err := templated.Validate(); err != nil {
+		t.Errorf("Templated severity should be valid: %s", err)
+	}
+}
+
+func TestContactPointTypedSettings(t *testing.T) {
+	p := &ContactPoint{
+		Type: "email",
+		Settings: map[string]interface{}{
+			"addresses":   "a@example.com; b@example.com,c@example.com",
+			"singleEmail": true,
+			"subject":     "Alert",
+		},
+	}
+
+	settings, err := p.TypedSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	email, ok := settings.(*EmailSettings)
+	if !ok {
+		t.Fatalf("Unexpected settings type %T", settings)
+	}
+	if !reflect.DeepEqual(email.Addresses, EmailAddresses{"a@example.com", "b@example.com", "c@example.com"}) || !email.SingleEmail || email.Subject != "Alert" {
+		t.Errorf("Unexpected settings: %+v", email)
+	}
+
+	if _, err := (&ContactPoint{Type: "carrier-pigeon"}).TypedSettings(); err == nil {
+		t.Error("Expected an error for an unsupported type")
+	}
+}
+
+func TestContactPointRedactedFields(t *testing.T) {
+	p := &ContactPoint{
+		Type: "webhook",
+		Settings: map[string]interface{}{
+			"url":                       "http://hook",
+			"password":                  "[REDACTED]",
+			"authorization_credentials": "[REDACTED]",
+			"username":                  "user",
+		},
+	}
+
+	redacted := p.RedactedFields()
+	sort.Strings(redacted)
+	if !reflect.DeepEqual(redacted, []string{"authorization_credentials", "password"}) {
+		t.Errorf("Unexpected redacted fields: %v", redacted)
+	}
+
+	settings, err := p.TypedSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	secure := settings.SecureFields()
+	sort.Strings(secure)
+	if !reflect.DeepEqual(secure, redacted) {
+		t.Errorf("Unexpected secure fields: %v", secure)
+	}
+	if settings.(*WebhookSettings).Password != RedactedValue {
+		t.Error("Redacted values should be decoded as is, to be kept by Grafana on update")
+	}
+}
@@ -0,0 +1,337 @@
+package gapi
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// maintenanceMuteTimingPrefix prefixes the names of the mute timings created for maintenance windows.
+const maintenanceMuteTimingPrefix = "maintenance-"
+
+// maintenanceMuteTimingNameFormat matches the names given by maintenanceMuteTimingName, telling the mute timings
+// created for maintenance windows apart from others sharing the prefix.
+var maintenanceMuteTimingNameFormat = regexp.MustCompile(`^` + maintenanceMuteTimingPrefix + `\d{8}T\d{4}-[0-9a-f]{8}$`)
+
+// maintenanceCreatedBy is the author of the silences created for maintenance windows.
+const maintenanceCreatedBy = "maintenance"
+
+// MaintenanceWindow is a handle on a scheduled maintenance window, muting the alerts matching its matchers
+// between Start and End, either through a silence or through a mute timing.
+type MaintenanceWindow struct {
+	client *Client
+
+	Matchers Matchers
+	Start    time.Time
+	End      time.Time
+	Comment  string
+	// SilenceID and AlertmanagerUID are set for maintenance windows using a silence.
+	SilenceID       string
+	AlertmanagerUID string
+	// MuteTimingName is set for maintenance windows using a mute timing.
+	MuteTimingName string
+}
+
+// ScheduleMaintenance silences the alerts matching the matchers in the Grafana Alertmanager between start and end.
+func (c *Client) ScheduleMaintenance(matchers Matchers, start, end time.Time, comment string) (*MaintenanceWindow, error) {
+	w := &MaintenanceWindow{
+		client:          c,
+		Matchers:        matchers,
+		Start:           start,
+		End:             end,
+		Comment:         comment,
+		AlertmanagerUID: GrafanaAlertmanagerUID,
+	}
+
+	id, err := c.NewSilence(w.AlertmanagerUID, w.silence())
+	if err != nil {
+		return nil, err
+	}
+	w.SilenceID = id
+
+	return w, nil
+}
+
+// ScheduleMaintenanceMuteTiming mutes the alerts matching the matchers between start and end with a one-off mute
+// timing, attached to the notification policies whose matchers include all of them, as well as their children.
+// Unlike silences, the mute timing only mutes notifications, alerts are still visible as firing.
+// Times are rounded to the minute and evaluated in UTC. Expired mute timings are removed by CleanupMaintenance.
+func (c *Client) ScheduleMaintenanceMuteTiming(matchers Matchers, start, end time.Time, comment string) (*MaintenanceWindow, error) {
+	if len(matchers) == 0 {
+		return nil, fmt.Errorf("a maintenance window requires at least one matcher")
+	}
+	if !end.After(start) {
+		return nil, fmt.Errorf("a maintenance window must end after it starts")
+	}
+
+	w := &MaintenanceWindow{
+		client:         c,
+		Matchers:       matchers,
+		Start:          start,
+		End:            end,
+		Comment:        comment,
+		MuteTimingName: maintenanceMuteTimingName(matchers, start),
+	}
+
+	// The mute timing must exist before being referenced by the notification policies.
+	mt := &MuteTiming{Name: w.MuteTimingName, TimeIntervals: maintenanceIntervals(start, end)}
+	if err := c.NewMuteTiming(mt); err != nil {
+		return nil, err
+	}
+	_, err := c.UpdateNotificationPolicyTree(func(tree *NotificationPolicyTree) error {
+		if !attachMuteTiming(tree.Routes, matchers, w.MuteTimingName, false) {
+			return fmt.Errorf("no notification policy matches %v", matchers)
+		}
+		return nil
+	})
+	if err != nil {
+		if deleteErr := c.DeleteMuteTiming(w.MuteTimingName); deleteErr != nil {
+			return nil, fmt.Errorf("%w (and failed to delete mute timing %s: %s)", err, w.MuteTimingName, deleteErr)
+		}
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// Expired returns true if the maintenance window is over at the given time.
+func (w *MaintenanceWindow) Expired(now time.Time) bool {
+	return !now.Before(w.End)
+}
+
+// Extend moves the end of the maintenance window.
+func (w *MaintenanceWindow) Extend(end time.Time) error {
+	if !end.After(w.Start) {
+		return fmt.Errorf("a maintenance window must end after it starts")
+	}
+
+	if w.MuteTimingName != "" {
+		mt := &MuteTiming{Name: w.MuteTimingName, TimeIntervals: maintenanceIntervals(w.Start, end)}
+		if err := w.client.UpdateMuteTiming(mt); err != nil {
+			return err
+		}
+		w.End = end
+		return nil
+	}
+
+	silence := w.silence()
+	silence.ID = w.SilenceID
+	silence.EndsAt = end
+	// The Alertmanager creates a new silence if the previous one has already expired.
+	id, err := w.client.UpdateSilence(w.AlertmanagerUID, silence)
+	if err != nil {
+		return err
+	}
+	w.SilenceID = id
+	w.End = end
+	return nil
+}
+
+// Cancel ends the maintenance window immediately, removing its silence or mute timing.
+func (w *MaintenanceWindow) Cancel() error {
+	if w.MuteTimingName != "" {
+		return w.client.removeMaintenanceMuteTiming(w.MuteTimingName)
+	}
+	return w.client.DeleteSilence(w.AlertmanagerUID, w.SilenceID)
+}
+
+func (w *MaintenanceWindow) silence() *Silence {
+	return &Silence{
+		Matchers:  w.Matchers,
+		StartsAt:  w.Start,
+		EndsAt:    w.End,
+		CreatedBy: maintenanceCreatedBy,
+		Comment:   w.Comment,
+	}
+}
+
+// CleanupMaintenance removes the mute timings of the maintenance windows over at the given time, detaching them from
+// the notification policies, and returns their names. Only mute timings created by ScheduleMaintenanceMuteTiming are
+// considered, and those whose time intervals were edited into another shape are skipped. Maintenance silences expire
+// by themselves.
+func (c *Client) CleanupMaintenance(now time.Time) ([]string, error) {
+	mts, err := c.MuteTimings()
+	if err != nil {
+		return nil, err
+	}
+
+	removed := make([]string, 0)
+	for _, mt := range mts {
+		if !maintenanceMuteTimingNameFormat.MatchString(mt.Name) {
+			continue
+		}
+		end, err := maintenanceIntervalsEnd(mt.TimeIntervals)
+		if err != nil || now.Before(end) {
+			continue
+		}
+		if err := c.removeMaintenanceMuteTiming(mt.Name); err != nil {
+			return removed, err
+		}
+		removed = append(removed, mt.Name)
+	}
+
+	return removed, nil
+}
+
+func (c *Client) removeMaintenanceMuteTiming(name string) error {
+	_, err := c.UpdateNotificationPolicyTree(func(tree *NotificationPolicyTree) error {
+		detachMuteTiming(tree.Routes, name)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return c.DeleteMuteTiming(name)
+}
+
+// attachMuteTiming adds the mute timing to the routes whose matchers include all of the given matchers,
+// and to all of their children as mute timings are not inherited. It returns false if no route matches.
+func attachMuteTiming(routes []SpecificPolicy, matchers Matchers, name string, parentMatched bool) bool {
+	attached := false
+	for i := range routes {
+		route := &routes[i]
+		matched := parentMatched || includesMatchers(route.ObjectMatchers, matchers)
+		if matched {
+			if !containsString(route.MuteTimeIntervals, name) {
+				route.MuteTimeIntervals = append(route.MuteTimeIntervals, name)
+			}
+			attached = true
+		}
+		if attachMuteTiming(route.Routes, matchers, name, matched) {
+			attached = true
+		}
+	}
+	return attached
+}
+
+// detachMuteTiming removes the mute timing from the routes and returns true if any route was changed.
+func detachMuteTiming(routes []SpecificPolicy, name string) bool {
+	detached := false
+	for i := range routes {
+		route := &routes[i]
+		intervals := make([]string, 0, len(route.MuteTimeIntervals))
+		for _, interval := range route.MuteTimeIntervals {
+			if interval != name {
+				intervals = append(intervals, interval)
+			}
+		}
+		if len(intervals) != len(route.MuteTimeIntervals) {
+			route.MuteTimeIntervals = intervals
+			detached = true
+		}
+		if detachMuteTiming(route.Routes, name) {
+			detached = true
+		}
+	}
+	return detached
+}
+
+func includesMatchers(matchers Matchers, included Matchers) bool {
+	for _, m := range included {
+		found := false
+		for _, other := range matchers {
+			if m == other {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func maintenanceMuteTimingName(matchers Matchers, start time.Time) string {
+	h := fnv.New32a()
+	for _, m := range matchers {
+		h.Write([]byte(m.String()))
+	}
+	return fmt.Sprintf("%s%s-%08x", maintenanceMuteTimingPrefix, start.UTC().Format("20060102T1504"), h.Sum32())
+}
+
+// maintenanceIntervals returns the time intervals covering the minutes between start and end, one per day.
+func maintenanceIntervals(start, end time.Time) []TimeInterval {
+	start = start.UTC().Truncate(time.Minute)
+	end = end.UTC()
+	if rounded := end.Truncate(time.Minute); rounded.Before(end) {
+		end = rounded.Add(time.Minute)
+	}
+
+	intervals := make([]TimeInterval, 0)
+	for day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC); day.Before(end); day = day.AddDate(0, 0, 1) {
+		interval := TimeInterval{
+			DaysOfMonth: []DayOfMonthRange{DayOfMonthRange(strconv.Itoa(day.Day()))},
+			Months:      []MonthRange{MonthRange(strconv.Itoa(int(day.Month())))},
+			Years:       []YearRange{YearRange(strconv.Itoa(day.Year()))},
+		}
+		next := day.AddDate(0, 0, 1)
+		if start.After(day) || end.Before(next) {
+			from, to := day, next
+			if start.After(from) {
+				from = start
+			}
+			if end.Before(to) {
+				to = end
+			}
+			interval.Times = []TimeRange{{
+				StartMinute: from.Format("15:04"),
+				EndMinute:   maintenanceClock(day, to),
+			}}
+		}
+		intervals = append(intervals, interval)
+	}
+	return intervals
+}
+
+// maintenanceClock formats the time of day of t within day, using 24:00 for the end of the day.
+func maintenanceClock(day, t time.Time) string {
+	if t.Equal(day.AddDate(0, 0, 1)) {
+		return "24:00"
+	}
+	return t.Format("15:04")
+}
+
+// maintenanceIntervalsEnd returns the end of the time intervals created by maintenanceIntervals.
+func maintenanceIntervalsEnd(intervals []TimeInterval) (time.Time, error) {
+	if len(intervals) == 0 {
+		return time.Time{}, fmt.Errorf("no time interval")
+	}
+	last := intervals[len(intervals)-1]
+	if len(last.Years) != 1 || len(last.Months) != 1 || len(last.DaysOfMonth) != 1 || len(last.Times) > 1 {
+		return time.Time{}, fmt.Errorf("not a maintenance time interval")
+	}
+
+	year, err := strconv.Atoi(string(last.Years[0]))
+	if err != nil {
+		return time.Time{}, err
+	}
+	month, err := strconv.Atoi(string(last.Months[0]))
+	if err != nil {
+		return time.Time{}, err
+	}
+	day, err := strconv.Atoi(string(last.DaysOfMonth[0]))
+	if err != nil {
+		return time.Time{}, err
+	}
+	end := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+	if len(last.Times) == 1 && last.Times[0].EndMinute != "24:00" {
+		clock, err := time.Parse("15:04", last.Times[0].EndMinute)
+		if err != nil {
+			return time.Time{}, err
+		}
+		end = time.Date(year, time.Month(month), day, clock.Hour(), clock.Minute(), 0, 0, time.UTC)
+	}
+	return end, nil
+}
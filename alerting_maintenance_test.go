@@ -0,0 +1,184 @@
+package gapi
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	maintenancePolicyTreeJSON = `
+{
+	"receiver": "default",
+	"routes": [
+		{
+			"receiver": "infra",
+			"object_matchers": [["team", "=", "infra"], ["env", "=", "prod"]],
+			"routes": [{"receiver": "infra-pager", "object_matchers": [["severity", "=", "critical"]]}]
+		},
+		{"receiver": "web", "object_matchers": [["team", "=", "web"]], "mute_time_intervals": ["weekends"]}
+	]
+}
+`
+)
+
+func TestScheduleMaintenance(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{
+		{200, `{"silenceID": "silence-1"}`},
+		{200, `{"silenceID": "silence-2"}`},
+		{200, ""},
+	})
+	defer server.Close()
+
+	start := time.Date(2022, 9, 1, 10, 0, 0, 0, time.UTC)
+	matchers := Matchers{{Type: MatchEqual, Name: "team", Value: "infra"}}
+	w, err := client.ScheduleMaintenance(matchers, start, start.Add(time.Hour), "Deploying")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.SilenceID != "silence-1" || w.MuteTimingName != "" {
+		t.Errorf("Unexpected maintenance window: %+v", w)
+	}
+
+	if err := w.Extend(start.Add(2 * time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if w.SilenceID != "silence-2" || !w.End.Equal(start.Add(2*time.Hour)) {
+		t.Errorf("Unexpected extended maintenance window: %+v", w)
+	}
+	silence := Silence{}
+	if err := json.Unmarshal([]byte(server.receivedRequests[1].body), &silence); err != nil {
+		t.Fatal(err)
+	}
+	if silence.ID != "silence-1" || !silence.EndsAt.Equal(w.End) || silence.Comment != "Deploying" {
+		t.Errorf("Unexpected silence update: %s", server.receivedRequests[1].body)
+	}
+
+	if err := w.Cancel(); err != nil {
+		t.Fatal(err)
+	}
+	req := server.receivedRequests[2]
+	if req.method != "DELETE" || req.path != "/api/alertmanager/grafana/api/v2/silence/silence-2" {
+		t.Errorf("Unexpected request: %s %s", req.method, req.path)
+	}
+}
+
+func TestScheduleMaintenanceMuteTiming(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{
+		{201, ""},
+		{200, maintenancePolicyTreeJSON},
+		{200, maintenancePolicyTreeJSON},
+		{202, ""},
+	})
+	defer server.Close()
+
+	start := time.Date(2022, 9, 1, 22, 30, 0, 0, time.UTC)
+	matchers := Matchers{{Type: MatchEqual, Name: "team", Value: "infra"}}
+	w, err := client.ScheduleMaintenanceMuteTiming(matchers, start, start.Add(26*time.Hour), "Migrating")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(w.MuteTimingName, "maintenance-20220901T2230-") {
+		t.Errorf("Unexpected mute timing name: %s", w.MuteTimingName)
+	}
+
+	mt := MuteTiming{}
+	if err := json.Unmarshal([]byte(server.receivedRequests[0].body), &mt); err != nil {
+		t.Fatal(err)
+	}
+	expectedIntervals := `[{"times":[{"start_time":"22:30","end_time":"24:00"}],"days_of_month":["1"],"months":["9"],"years":["2022"]},{"days_of_month":["2"],"months":["9"],"years":["2022"]},{"times":[{"start_time":"00:00","end_time":"00:30"}],"days_of_month":["3"],"months":["9"],"years":["2022"]}]`
+	if intervals, _ := json.Marshal(mt.TimeIntervals); string(intervals) != expectedIntervals {
+		t.Errorf("Unexpected time intervals: %s", intervals)
+	}
+
+	tree := NotificationPolicyTree{}
+	if err := json.Unmarshal([]byte(server.receivedRequests[3].body), &tree); err != nil {
+		t.Fatal(err)
+	}
+	infra := tree.Routes[0]
+	if !containsString(infra.MuteTimeIntervals, w.MuteTimingName) || !containsString(infra.Routes[0].MuteTimeIntervals, w.MuteTimingName) {
+		t.Errorf("Mute timing not attached to the matching routes: %s", server.receivedRequests[3].body)
+	}
+	if len(tree.Routes[1].MuteTimeIntervals) != 1 {
+		t.Errorf("Mute timing attached to a route not matching: %s", server.receivedRequests[3].body)
+	}
+}
+
+func TestScheduleMaintenanceMuteTimingNoMatchingRoute(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{
+		{201, ""},
+		{200, maintenancePolicyTreeJSON},
+		{204, ""},
+	})
+	defer server.Close()
+
+	start := time.Now()
+	matchers := Matchers{{Type: MatchEqual, Name: "team", Value: "data"}}
+	if _, err := client.ScheduleMaintenanceMuteTiming(matchers, start, start.Add(time.Hour), ""); err == nil {
+		t.Error("Expected an error when no route matches")
+	}
+	if req := server.receivedRequests[2]; req.method != "DELETE" {
+		t.Errorf("Expected the mute timing to be deleted, got %s %s", req.method, req.path)
+	}
+}
+
+func TestCleanupMaintenance(t *testing.T) {
+	start := time.Date(2022, 9, 1, 10, 0, 0, 0, time.UTC)
+	expiredName := maintenanceMuteTimingName(Matchers{{Type: MatchEqual, Name: "team", Value: "infra"}}, start)
+	ongoingName := maintenanceMuteTimingName(Matchers{{Type: MatchEqual, Name: "team", Value: "web"}}, start)
+	editedName := maintenanceMuteTimingName(Matchers{{Type: MatchEqual, Name: "team", Value: "data"}}, start)
+	expired, _ := json.Marshal(maintenanceIntervals(start, start.Add(time.Hour)))
+	ongoing, _ := json.Marshal(maintenanceIntervals(start, start.Add(48*time.Hour)))
+	muteTimings := `[
+		{"name": "weekends", "time_intervals": [{"weekdays": ["saturday", "sunday"]}]},
+		{"name": "maintenance-nightly", "time_intervals": [{"times": [{"start_time": "02:00", "end_time": "04:00"}]}]},
+		{"name": "` + editedName + `", "time_intervals": [{"weekdays": ["monday"]}]},
+		{"name": "` + expiredName + `", "time_intervals": ` + string(expired) + `},
+		{"name": "` + ongoingName + `", "time_intervals": ` + string(ongoing) + `}
+	]`
+	tree := `{"receiver": "default", "routes": [{"receiver": "infra", "mute_time_intervals": ["weekends", "` + expiredName + `"]}]}`
+
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{
+		{200, muteTimings},
+		{200, tree},
+		{200, tree},
+		{202, ""},
+		{204, ""},
+	})
+	defer server.Close()
+
+	removed, err := client.CleanupMaintenance(start.Add(2 * time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 1 || removed[0] != expiredName {
+		t.Errorf("Unexpected removed mute timings: %v", removed)
+	}
+
+	expectedTree := `{"receiver":"default","routes":[{"receiver":"infra","mute_time_intervals":["weekends"],"continue":false}]}`
+	if body := server.receivedRequests[3].body; body != expectedTree {
+		t.Errorf("Unexpected notification policy tree: %s", body)
+	}
+	req := server.receivedRequests[4]
+	if req.method != "DELETE" || req.path != "/api/v1/provisioning/mute-timings/"+expiredName {
+		t.Errorf("Unexpected request: %s %s", req.method, req.path)
+	}
+}
+
+func TestMaintenanceIntervalsEnd(t *testing.T) {
+	start := time.Date(2022, 12, 31, 23, 0, 0, 0, time.UTC)
+	for _, end := range []time.Time{
+		start.Add(30 * time.Minute),
+		start.Add(time.Hour),
+		start.Add(25*time.Hour + 15*time.Minute),
+	} {
+		actual, err := maintenanceIntervalsEnd(maintenanceIntervals(start, end))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !actual.Equal(end) {
+			t.Errorf("Expected end %s, got %s", end, actual)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package gapi
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var matcherLabelName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*`)
+
+// ParseMatchers parses matchers in the Prometheus format, e.g. `{team="a", severity=~"crit|warn"}`.
+// The braces are optional, and so are the quotes around values without commas or braces.
+func ParseMatchers(s string) (Matchers, error) {
+	input := strings.TrimSpace(s)
+	if strings.HasPrefix(input, "{") {
+		if !strings.HasSuffix(input, "}") {
+			return nil, fmt.Errorf("missing closing brace in matchers %q", s)
+		}
+		input = input[1 : len(input)-1]
+	}
+
+	matchers := make(Matchers, 0)
+	for {
+		input = strings.TrimLeft(input, " \t\n")
+		if input == "" {
+			return matchers, nil
+		}
+
+		matcher, rest, err := parseMatcher(input)
+		if err != nil {
+			return nil, fmt.Errorf("invalid matchers %q: %w", s, err)
+		}
+		matchers = append(matchers, matcher)
+
+		rest = strings.TrimLeft(rest, " \t\n")
+		if rest != "" && !strings.HasPrefix(rest, ",") {
+			return nil, fmt.Errorf("invalid matchers %q: expected a comma before %q", s, rest)
+		}
+		input = strings.TrimPrefix(rest, ",")
+	}
+}
+
+// ParseMatcher parses a single matcher in the Prometheus format, e.g. `severity=~"crit|warn"`.
+func ParseMatcher(s string) (Matcher, error) {
+	matcher, rest, err := parseMatcher(strings.TrimSpace(s))
+	if err != nil {
+		return Matcher{}, fmt.Errorf("invalid matcher %q: %w", s, err)
+	}
+	if rest = strings.TrimSpace(rest); rest != "" {
+		return Matcher{}, fmt.Errorf("invalid matcher %q: unexpected %q", s, rest)
+	}
+	return matcher, nil
+}
+
+// parseMatcher parses the matcher at the start of the input and returns the rest of the input.
+func parseMatcher(input string) (Matcher, string, error) {
+	name := matcherLabelName.FindString(input)
+	if name == "" {
+		return Matcher{}, "", fmt.Errorf("expected a label name at %q", input)
+	}
+	input = strings.TrimLeft(input[len(name):], " \t\n")
+
+	operator := ""
+	for _, op := range []string{"=~", "!~", "!=", "="} {
+		if strings.HasPrefix(input, op) {
+			operator = op
+			break
+		}
+	}
+	if operator == "" {
+		return Matcher{}, "", fmt.Errorf("expected an operator after label %s", name)
+	}
+	matchType, err := parseMatchType(operator)
+	if err != nil {
+		return Matcher{}, "", err
+	}
+	input = strings.TrimLeft(input[len(operator):], " \t\n")
+
+	value, rest, err := parseMatcherValue(input)
+	if err != nil {
+		return Matcher{}, "", fmt.Errorf("label %s: %w", name, err)
+	}
+
+	matcher := Matcher{Type: matchType, Name: name, Value: value}
+	if matchType == MatchRegexp || matchType == MatchNotRegexp {
+		if _, err := matcher.regexp(); err != nil {
+			return Matcher{}, "", err
+		}
+	}
+	return matcher, rest, nil
+}
+
+// parseMatcherValue parses the quoted or unquoted value at the start of the input and returns the rest of the input.
+func parseMatcherValue(input string) (string, string, error) {
+	if !strings.HasPrefix(input, `"`) {
+		end := strings.IndexAny(input, ",}")
+		if end < 0 {
+			end = len(input)
+		}
+		value := strings.TrimSpace(input[:end])
+		if strings.Contains(value, `"`) {
+			return "", "", fmt.Errorf("invalid unquoted value %s", value)
+		}
+		return value, input[end:], nil
+	}
+
+	escaped := false
+	for i := 1; i < len(input); i++ {
+		switch {
+		case escaped:
+			escaped = false
+		case input[i] == '\\':
+			escaped = true
+		case input[i] == '"':
+			value, err := strconv.Unquote(input[:i+1])
+			if err != nil {
+				return "", "", fmt.Errorf("invalid quoted value %s", input[:i+1])
+			}
+			return value, input[i+1:], nil
+		}
+	}
+	return "", "", fmt.Errorf("missing closing quote in %s", input)
+}
+
+// String returns the matchers in the Prometheus format, e.g. `{team="a", severity=~"crit|warn"}`.
+func (m Matchers) String() string {
+	matchers := make([]string, len(m))
+	for i, matcher := range m {
+		matchers[i] = matcher.String()
+	}
+	return "{" + strings.Join(matchers, ", ") + "}"
+}
+
+// String returns the matcher in the Prometheus format, e.g. `team=~"a|b"`.
+func (m Matcher) String() string {
+	return fmt.Sprintf("%s%s%s", m.Name, m.Type, strconv.Quote(m.Value))
+}
+
+// Matches returns true if the labels satisfy all the matchers. Missing labels have an empty value,
+// and regular expressions must match whole values, as in the Alertmanager.
+func (m Matchers) Matches(labels map[string]string) (bool, error) {
+	for _, matcher := range m {
+		ok, err := matcher.Matches(labels)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// Matches returns true if the labels satisfy the matcher. A missing label has an empty value,
+// and regular expressions must match the whole value, as in the Alertmanager.
+func (m Matcher) Matches(labels map[string]string) (bool, error) {
+	value := labels[m.Name]
+	switch m.Type {
+	case MatchEqual:
+		return value == m.Value, nil
+	case MatchNotEqual:
+		return value != m.Value, nil
+	case MatchRegexp, MatchNotRegexp:
+		re, err := m.regexp()
+		if err != nil {
+			return false, err
+		}
+		return re.MatchString(value) == (m.Type == MatchRegexp), nil
+	default:
+		return false, fmt.Errorf("unknown match type %d in matcher on %s", int(m.Type), m.Name)
+	}
+}
+
+// regexp returns the anchored regular expression of the matcher.
+func (m Matcher) regexp() (*regexp.Regexp, error) {
+	re, err := regexp.Compile("^(?:" + m.Value + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression in matcher on %s: %w", m.Name, err)
+	}
+	return re, nil
+}
@@ -0,0 +1,137 @@
+package gapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseMatchers(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected Matchers
+	}{
+		{
+			input: `{team="a", severity=~"crit|warn"}`,
+			expected: Matchers{
+				{Type: MatchEqual, Name: "team", Value: "a"},
+				{Type: MatchRegexp, Name: "severity", Value: "crit|warn"},
+			},
+		},
+		{
+			input: `env != "prod",  region!~"eu-.*" ,`,
+			expected: Matchers{
+				{Type: MatchNotEqual, Name: "env", Value: "prod"},
+				{Type: MatchNotRegexp, Name: "region", Value: "eu-.*"},
+			},
+		},
+		{
+			input: `{summary="a \"quoted\", value", team=infra}`,
+			expected: Matchers{
+				{Type: MatchEqual, Name: "summary", Value: `a "quoted", value`},
+				{Type: MatchEqual, Name: "team", Value: "infra"},
+			},
+		},
+		{
+			input:    `{}`,
+			expected: Matchers{},
+		},
+	}
+
+	for _, c := range cases {
+		matchers, err := ParseMatchers(c.input)
+		if err != nil {
+			t.Errorf("%s: %s", c.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(matchers, c.expected) {
+			t.Errorf("%s: unexpected matchers %v", c.input, matchers)
+		}
+	}
+}
+
+func TestParseMatchersErrors(t *testing.T) {
+	for _, input := range []string{
+		`{team="a"`,
+		`team`,
+		`team=="a"`,
+		`team="a" severity="b"`,
+		`team="a`,
+		`team=~"(a"`,
+		`1team="a"`,
+	} {
+		if matchers, err := ParseMatchers(input); err == nil {
+			t.Errorf("%s: expected an error, got %v", input, matchers)
+		}
+	}
+}
+
+func TestParseMatcher(t *testing.T) {
+	matcher, err := ParseMatcher(`severity =~ "crit|warn"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if matcher != (Matcher{Type: MatchRegexp, Name: "severity", Value: "crit|warn"}) {
+		t.Errorf("Unexpected matcher: %v", matcher)
+	}
+
+	if _, err := ParseMatcher(`team="a", env="b"`); err == nil {
+		t.Error("Expected an error for several matchers")
+	}
+}
+
+func TestMatchersString(t *testing.T) {
+	input := `{team="a", summary=~"say \"hi\"", env!="prod", region!~"eu-.*"}`
+	matchers, err := ParseMatchers(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := matchers.String(); s != input {
+		t.Errorf("Unexpected string: %s", s)
+	}
+
+	if s := (Matcher{Type: MatchType(42), Name: "a", Value: "b"}).String(); s != `aMatchType(42)"b"` {
+		t.Errorf("Unexpected string for an unknown match type: %s", s)
+	}
+	if _, err := json.Marshal(Matchers{{Type: MatchType(42), Name: "a", Value: "b"}}); err == nil {
+		t.Error("Expected an error when marshalling an unknown match type")
+	}
+}
+
+func TestMatchersMatches(t *testing.T) {
+	labels := map[string]string{"team": "infra", "severity": "critical"}
+	cases := map[string]bool{
+		`{team="infra"}`:                     true,
+		`{team="infra", severity="warning"}`: false,
+		`{team!="web"}`:                      true,
+		`{env=""}`:                           true,
+		`{env!=""}`:                          false,
+		`{severity=~"crit"}`:                 false,
+		`{severity=~"crit.*"}`:               true,
+		`{severity=~"warning|critical"}`:     true,
+		`{severity!~"crit"}`:                 true,
+		`{severity!~"crit.*|warn"}`:          false,
+		`{}`:                                 true,
+	}
+
+	for input, expected := range cases {
+		matchers, err := ParseMatchers(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ok, err := matchers.Matches(labels)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != expected {
+			t.Errorf("%s: expected %t, got %t", input, expected, ok)
+		}
+	}
+
+	if _, err := (Matchers{{Type: MatchRegexp, Name: "team", Value: "(a"}}).Matches(labels); err == nil {
+		t.Error("Expected an error for an invalid regular expression")
+	}
+	if _, err := (Matchers{{Type: MatchType(42), Name: "team", Value: "a"}}).Matches(labels); err == nil {
+		t.Error("Expected an error for an unknown match type")
+	}
+}
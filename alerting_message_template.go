@@ -33,7 +33,8 @@ func (c *Client) MessageTemplate(name string) (*AlertingMessageTemplate, error)
 	return &t, err
 }
 
-// SetMessageTemplate creates or updates a message template.
+// SetMessageTemplate creates or updates a message template. The content isn't validated until a notification
+// uses it, see ValidateMessageTemplate and PreviewMessageTemplate to check it beforehand.
 func (c *Client) SetMessageTemplate(name, content string) error {
 	req := struct {
 		Template string `json:"template"`
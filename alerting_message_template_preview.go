@@ -0,0 +1,437 @@
+package gapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+)
+
+// Alert statuses used in the template data.
+const (
+	TemplateAlertStatusFiring   = "firing"
+	TemplateAlertStatusResolved = "resolved"
+)
+
+// defaultMessageTemplateNames are the templates built into Grafana, which message templates can reference.
+// They render as placeholders offline.
+var defaultMessageTemplateNames = []string{
+	"default.title",
+	"default.message",
+	"__subject",
+	"__text_values_list",
+	"__text_alert_list",
+	"__text_alert_list_markdown",
+	"teams.default.message",
+}
+
+// TemplateData mirrors the data Grafana passes to notification templates, as the dot of the template.
+type TemplateData struct {
+	Receiver          string
+	Status            string
+	Alerts            TemplateAlerts
+	GroupLabels       TemplateKV
+	CommonLabels      TemplateKV
+	CommonAnnotations TemplateKV
+	ExternalURL       string
+	GroupKey          string
+	// TruncatedAlerts is the number of alerts left out of Alerts, by integrations limiting the size of notifications.
+	TruncatedAlerts int
+}
+
+// TemplateAlert mirrors an alert in the template data.
+type TemplateAlert struct {
+	Status       string
+	Labels       TemplateKV
+	Annotations  TemplateKV
+	StartsAt     time.Time
+	EndsAt       time.Time
+	GeneratorURL string
+	Fingerprint  string
+	SilenceURL   string
+	DashboardURL string
+	PanelURL     string
+	Values       map[string]float64
+	ValueString  string
+	ImageURL     string
+	// EmbeddedImage is the name of the image attached to the notification, when the integration embeds images.
+	EmbeddedImage string
+	OrgID         *int64
+}
+
+// TemplateAlerts is a list of alerts in the template data.
+type TemplateAlerts []TemplateAlert
+
+// Firing returns the firing alerts.
+func (as TemplateAlerts) Firing() []TemplateAlert {
+	return as.withStatus(TemplateAlertStatusFiring)
+}
+
+// Resolved returns the resolved alerts.
+func (as TemplateAlerts) Resolved() []TemplateAlert {
+	return as.withStatus(TemplateAlertStatusResolved)
+}
+
+func (as TemplateAlerts) withStatus(status string) []TemplateAlert {
+	res := make([]TemplateAlert, 0)
+	for _, a := range as {
+		if a.Status == status {
+			res = append(res, a)
+		}
+	}
+	return res
+}
+
+// TemplatePair is a label or annotation name with its value.
+type TemplatePair struct {
+	Name  string
+	Value string
+}
+
+// TemplatePairs is a list of label or annotation pairs.
+type TemplatePairs []TemplatePair
+
+// Names returns the names of the pairs.
+func (ps TemplatePairs) Names() []string {
+	names := make([]string, len(ps))
+	for i, p := range ps {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// Values returns the values of the pairs.
+func (ps TemplatePairs) Values() []string {
+	values := make([]string, len(ps))
+	for i, p := range ps {
+		values[i] = p.Value
+	}
+	return values
+}
+
+// TemplateKV is a set of labels or annotations in the template data.
+type TemplateKV map[string]string
+
+// SortedPairs returns the pairs sorted by name, with alertname first.
+func (kv TemplateKV) SortedPairs() TemplatePairs {
+	pairs := make(TemplatePairs, 0, len(kv))
+	for name, value := range kv {
+		pairs = append(pairs, TemplatePair{Name: name, Value: value})
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Name == "alertname" || pairs[j].Name == "alertname" {
+			return pairs[i].Name == "alertname"
+		}
+		return pairs[i].Name < pairs[j].Name
+	})
+	return pairs
+}
+
+// Remove returns a copy of the set without the given names.
+func (kv TemplateKV) Remove(names []string) TemplateKV {
+	res := make(TemplateKV, len(kv))
+	for name, value := range kv {
+		if !containsString(names, name) {
+			res[name] = value
+		}
+	}
+	return res
+}
+
+// Names returns the sorted names of the set.
+func (kv TemplateKV) Names() []string {
+	return kv.SortedPairs().Names()
+}
+
+// Values returns the values of the set, sorted by name.
+func (kv TemplateKV) Values() []string {
+	return kv.SortedPairs().Values()
+}
+
+// NewTemplateData returns the template data of a notification of the alerts to the receiver, with its status
+// and common labels and annotations derived from the alerts, as Grafana does.
+func NewTemplateData(receiver string, alerts []TemplateAlert) *TemplateData {
+	data := &TemplateData{
+		Receiver:          receiver,
+		Status:            TemplateAlertStatusResolved,
+		Alerts:            alerts,
+		GroupLabels:       TemplateKV{},
+		CommonLabels:      TemplateKV{},
+		CommonAnnotations: TemplateKV{},
+		ExternalURL:       "http://localhost:3000/",
+	}
+	for i, a := range alerts {
+		if a.Status == TemplateAlertStatusFiring {
+			data.Status = TemplateAlertStatusFiring
+		}
+		if i == 0 {
+			data.CommonLabels = commonTemplateKV(a.Labels, a.Labels)
+			data.CommonAnnotations = commonTemplateKV(a.Annotations, a.Annotations)
+			continue
+		}
+		data.CommonLabels = commonTemplateKV(data.CommonLabels, a.Labels)
+		data.CommonAnnotations = commonTemplateKV(data.CommonAnnotations, a.Annotations)
+	}
+	return data
+}
+
+// SampleTemplateData returns template data with a single firing alert, similar to Grafana's test notifications.
+func SampleTemplateData() *TemplateData {
+	orgID := int64(1)
+	return NewTemplateData("sample-receiver", []TemplateAlert{{
+		Status:       TemplateAlertStatusFiring,
+		Labels:       TemplateKV{"alertname": "TestAlert", "instance": "Grafana"},
+		Annotations:  TemplateKV{"summary": "Notification test"},
+		StartsAt:     time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		GeneratorURL: "http://localhost:3000/alerting/grafana/sample/view",
+		Fingerprint:  "57c6d9296de2ad39",
+		SilenceURL:   "http://localhost:3000/alerting/silence/new",
+		Values:       map[string]float64{"B": 1},
+		ValueString:  "[ var='B' labels={} value=1 ]",
+		OrgID:        &orgID,
+	}})
+}
+
+func commonTemplateKV(kv, other TemplateKV) TemplateKV {
+	res := TemplateKV{}
+	for name, value := range kv {
+		if v, ok := other[name]; ok && v == value {
+			res[name] = value
+		}
+	}
+	return res
+}
+
+// ValidateMessageTemplate parses the content of a message template offline and returns the names of the templates
+// it defines. Each defined template is executed against SampleTemplateData to catch references to fields or
+// methods missing from Grafana's template data. Only the template functions of the Alertmanager are available.
+func ValidateMessageTemplate(content string) ([]string, error) {
+	tmpl, err := parseMessageTemplate(content)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0)
+	for _, t := range tmpl.Templates() {
+		if t.Name() != tmpl.Name() && !containsString(defaultMessageTemplateNames, t.Name()) {
+			names = append(names, t.Name())
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("message template defines no template, use {{ define \"name\" }} ... {{ end }}")
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := tmpl.ExecuteTemplate(&bytes.Buffer{}, name, SampleTemplateData()); err != nil {
+			return nil, fmt.Errorf("invalid message template %s: %w", name, err)
+		}
+	}
+	return names, nil
+}
+
+// RenderMessageTemplate renders the named template defined by the content of a message template offline, with the
+// given data. Grafana's built-in templates, such as default.message, render as placeholders.
+func RenderMessageTemplate(content, name string, data *TemplateData) (string, error) {
+	tmpl, err := parseMessageTemplate(content)
+	if err != nil {
+		return "", err
+	}
+	if tmpl.Lookup(name) == nil {
+		return "", fmt.Errorf("message template does not define %s", name)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func parseMessageTemplate(content string) (*template.Template, error) {
+	tmpl := template.New("").Option("missingkey=zero").Funcs(messageTemplateFuncs)
+	for _, name := range defaultMessageTemplateNames {
+		template.Must(tmpl.New(name).Parse(fmt.Sprintf("[%s]", name)))
+	}
+	if _, err := tmpl.Parse(content); err != nil {
+		return nil, fmt.Errorf("invalid message template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// messageTemplateFuncs are the template functions of the Alertmanager, which Grafana makes available.
+var messageTemplateFuncs = template.FuncMap{
+	"toUpper":   strings.ToUpper,
+	"toLower":   strings.ToLower,
+	"title":     titleCase,
+	"trimSpace": strings.TrimSpace,
+	"join": func(sep string, s []string) string {
+		return strings.Join(s, sep)
+	},
+	"match": regexp.MatchString,
+	"safeHtml": func(text string) string {
+		return text
+	},
+	"reReplaceAll": func(pattern, repl, text string) (string, error) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return "", err
+		}
+		return re.ReplaceAllString(text, repl), nil
+	},
+	"stringSlice": func(s ...string) []string {
+		return s
+	},
+	"date": func(layout string, t time.Time) string {
+		return t.Format(layout)
+	},
+	"tz": func(name string, t time.Time) (time.Time, error) {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return t.In(loc), nil
+	},
+	"since":            time.Since,
+	"humanizeDuration": humanizeDuration,
+}
+
+// humanizeDuration formats a number of seconds like the Alertmanager, e.g. "1h 2m 3s" or "250ms".
+func humanizeDuration(i interface{}) (string, error) {
+	v, err := templateFloat(i)
+	if err != nil {
+		return "", err
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return fmt.Sprintf("%.4g", v), nil
+	}
+	if v == 0 {
+		return fmt.Sprintf("%.4gs", v), nil
+	}
+	if math.Abs(v) >= 1 {
+		sign := ""
+		if v < 0 {
+			sign = "-"
+			v = -v
+		}
+		duration := int64(v)
+		seconds := duration % 60
+		minutes := (duration / 60) % 60
+		hours := (duration / 60 / 60) % 24
+		days := duration / 60 / 60 / 24
+		switch {
+		case days != 0:
+			return fmt.Sprintf("%s%dd %dh %dm %ds", sign, days, hours, minutes, seconds), nil
+		case hours != 0:
+			return fmt.Sprintf("%s%dh %dm %ds", sign, hours, minutes, seconds), nil
+		case minutes != 0:
+			return fmt.Sprintf("%s%dm %ds", sign, minutes, seconds), nil
+		}
+		return fmt.Sprintf("%s%.4gs", sign, v), nil
+	}
+
+	prefix := ""
+	for _, p := range []string{"m", "u", "n", "p", "f", "a", "z", "y"} {
+		if math.Abs(v) >= 1 {
+			break
+		}
+		prefix = p
+		v *= 1000
+	}
+	return fmt.Sprintf("%.4g%ss", v, prefix), nil
+}
+
+// templateFloat converts the argument of a template function to a number, as the Alertmanager does.
+func templateFloat(i interface{}) (float64, error) {
+	switch v := i.(type) {
+	case float64:
+		return v, nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	case int:
+		return float64(v), nil
+	case uint:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case time.Duration:
+		return v.Seconds(), nil
+	default:
+		return 0, fmt.Errorf("can't convert %T to float", v)
+	}
+}
+
+// titleCase upper-cases the first letter of each word, like the deprecated strings.Title used by the Alertmanager.
+func titleCase(s string) string {
+	prev := ' '
+	return strings.Map(func(r rune) rune {
+		isStart := unicode.IsSpace(prev) || unicode.IsPunct(prev)
+		prev = r
+		if isStart {
+			return unicode.ToTitle(r)
+		}
+		return r
+	}, s)
+}
+
+// MessageTemplateTestAlert is a sample alert to render a message template with.
+type MessageTemplateTestAlert struct {
+	Labels       map[string]string `json:"labels,omitempty"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
+
+// MessageTemplateTestResults holds the templates rendered by Grafana, and the errors of those that failed.
+type MessageTemplateTestResults struct {
+	Results []MessageTemplateTestResult `json:"results"`
+	Errors  []MessageTemplateTestError  `json:"errors"`
+}
+
+// MessageTemplateTestResult is the text rendered by Grafana for a template.
+type MessageTemplateTestResult struct {
+	Name string `json:"name"`
+	Text string `json:"text"`
+}
+
+// MessageTemplateTestError is the error of a template which Grafana failed to parse or render.
+type MessageTemplateTestError struct {
+	Name    string `json:"name"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// PreviewMessageTemplate renders the content of a message template in Grafana against the sample alerts, without
+// saving it. The name is the name the message template would be saved with. Templates failing to render are
+// reported in the errors of the results rather than as an error.
+func (c *Client) PreviewMessageTemplate(name, content string, alerts []MessageTemplateTestAlert) (*MessageTemplateTestResults, error) {
+	if alerts == nil {
+		alerts = make([]MessageTemplateTestAlert, 0)
+	}
+	req := struct {
+		Name     string                     `json:"name"`
+		Template string                     `json:"template"`
+		Alerts   []MessageTemplateTestAlert `json:"alerts"`
+	}{Name: name, Template: content, Alerts: alerts}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MessageTemplateTestResults{}
+	err = c.request("POST", "/api/alertmanager/grafana/config/api/v1/templates/test", nil, bytes.NewBuffer(body), result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,165 @@
+package gapi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gobs/pretty"
+)
+
+func TestValidateMessageTemplate(t *testing.T) {
+	t.Run("valid templates succeed", func(t *testing.T) {
+		names, err := ValidateMessageTemplate(validMessageTemplate)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Join(names, ",") != "custom.message,custom.title" {
+			t.Errorf("unexpected template names: %v", names)
+		}
+	})
+
+	t.Run("templates using all the template data succeed", func(t *testing.T) {
+		content := `{{ define "a" }}{{ .GroupKey }} {{ .TruncatedAlerts }}{{ range .Alerts }}{{ .ImageURL }}{{ .EmbeddedImage }}{{ .OrgID }}{{ end }}{{ end }}`
+
+		if _, err := ValidateMessageTemplate(content); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("invalid templates fail", func(t *testing.T) {
+		cases := map[string]string{
+			"syntax":           `{{ define "a" }}{{ .Status }`,
+			"unknown function": `{{ define "a" }}{{ .Status | shout }}{{ end }}`,
+			"unknown field":    `{{ define "a" }}{{ range .Alerts }}{{ .Severity }}{{ end }}{{ end }}`,
+			"unknown template": `{{ define "a" }}{{ template "b" . }}{{ end }}`,
+			"no definition":    `{{ .Status }}`,
+		}
+		for name, content := range cases {
+			if _, err := ValidateMessageTemplate(content); err == nil {
+				t.Errorf("%s: expected an error", name)
+			}
+		}
+	})
+}
+
+func TestRenderMessageTemplate(t *testing.T) {
+	t.Run("rendering with sample data succeeds", func(t *testing.T) {
+		text, err := RenderMessageTemplate(validMessageTemplate, "custom.title", SampleTemplateData())
+
+		if err != nil {
+			t.Fatal(err)
+		}
+		if text != "[FIRING:1] TestAlert" {
+			t.Errorf("unexpected text: %q", text)
+		}
+	})
+
+	t.Run("rendering resolved alerts succeeds", func(t *testing.T) {
+		data := NewTemplateData("team-a", []TemplateAlert{
+			{Status: TemplateAlertStatusResolved, Labels: TemplateKV{"alertname": "HighCPU", "team": "a"}},
+			{Status: TemplateAlertStatusResolved, Labels: TemplateKV{"alertname": "HighCPU", "team": "b"}},
+		})
+
+		text, err := RenderMessageTemplate(validMessageTemplate, "custom.message", data)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := "Resolved: alertname=HighCPU team=a \nResolved: alertname=HighCPU team=b \n[default.message]"
+		if text != expected {
+			t.Errorf("unexpected text: %q", text)
+		}
+		if data.Status != TemplateAlertStatusResolved || len(data.CommonLabels) != 1 {
+			t.Errorf("unexpected template data: %#v", data)
+		}
+	})
+
+	t.Run("rendering an undefined template fails", func(t *testing.T) {
+		_, err := RenderMessageTemplate(validMessageTemplate, "custom.other", SampleTemplateData())
+
+		if err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestMessageTemplateHumanizeDuration(t *testing.T) {
+	content := `{{ define "a" }}{{ range .Alerts }}{{ humanizeDuration .Values.B }}{{ end }}{{ end }}`
+	cases := map[float64]string{
+		0:      "0s",
+		1:      "1s",
+		1.5:    "1.5s",
+		0.25:   "250ms",
+		-90:    "-1m 30s",
+		3723:   "1h 2m 3s",
+		90061:  "1d 1h 1m 1s",
+		0.0005: "500us",
+	}
+
+	for value, expected := range cases {
+		data := NewTemplateData("receiver", []TemplateAlert{{Values: map[string]float64{"B": value}}})
+		text, err := RenderMessageTemplate(content, "a", data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if text != expected {
+			t.Errorf("expected %q for %v, got %q", expected, value, text)
+		}
+	}
+
+	text, err := RenderMessageTemplate(`{{ define "a" }}{{ humanizeDuration "60" }}{{ end }}`, "a", SampleTemplateData())
+	if err != nil || text != "1m 0s" {
+		t.Errorf("unexpected text for a string: %q, %v", text, err)
+	}
+}
+
+func TestPreviewMessageTemplate(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, previewMessageTemplateJSON}})
+	defer server.Close()
+
+	alerts := []MessageTemplateTestAlert{{Labels: map[string]string{"alertname": "TestAlert"}}}
+	res, err := client.PreviewMessageTemplate("custom", validMessageTemplate, alerts)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(pretty.PrettyFormat(res))
+	req := server.receivedRequests[0]
+	if req.method != "POST" || req.path != "/api/alertmanager/grafana/config/api/v1/templates/test" {
+		t.Errorf("unexpected request: %s %s", req.method, req.path)
+	}
+	if !strings.Contains(req.body, `"name":"custom"`) || !strings.Contains(req.body, `"labels":{"alertname":"TestAlert"}`) {
+		t.Errorf("unexpected request body: %s", req.body)
+	}
+	if len(res.Results) != 1 || res.Results[0].Text != "[FIRING:1] TestAlert" {
+		t.Errorf("unexpected results: %#v", res.Results)
+	}
+	if len(res.Errors) != 1 || res.Errors[0].Kind != "execution_error" {
+		t.Errorf("unexpected errors: %#v", res.Errors)
+	}
+}
+
+const (
+	validMessageTemplate = `{{ define "custom.title" }}[{{ .Status | toUpper }}:{{ .Alerts.Firing | len }}] {{ .CommonLabels.alertname }}{{ end }}
+{{ define "custom.message" }}{{ range .Alerts.Resolved }}Resolved: {{ range .Labels.SortedPairs }}{{ .Name }}={{ .Value }} {{ end }}
+{{ end }}{{ template "default.message" . }}{{ end }}`
+
+	previewMessageTemplateJSON = `
+{
+	"results": [
+		{
+			"name": "custom.title",
+			"text": "[FIRING:1] TestAlert"
+		}
+	],
+	"errors": [
+		{
+			"name": "custom.message",
+			"kind": "execution_error",
+			"message": "template: custom.message:1:42: executing \"custom.message\" at <.Severity>: can't evaluate field Severity"
+		}
+	]
+}
+`
+)
@@ -20,6 +20,8 @@ type TimeInterval struct {
 	DaysOfMonth []DayOfMonthRange `json:"days_of_month,omitempty"`
 	Months      []MonthRange      `json:"months,omitempty"`
 	Years       []YearRange       `json:"years,omitempty"`
+	// Location is the time zone the interval is evaluated in, e.g. "Europe/Paris". It defaults to UTC.
+	Location string `json:"location,omitempty"`
 }
 
 // TimeRange represents a range of minutes within a 1440 minute day, exclusive of the End minute.
@@ -0,0 +1,452 @@
+package gapi
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// muteTimingSearchDays is how far ahead NextMuteWindows looks for active windows.
+const muteTimingSearchDays = 5 * 366
+
+var weekdayNames = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+var monthNames = map[string]time.Month{
+	"january":   time.January,
+	"february":  time.February,
+	"march":     time.March,
+	"april":     time.April,
+	"may":       time.May,
+	"june":      time.June,
+	"july":      time.July,
+	"august":    time.August,
+	"september": time.September,
+	"october":   time.October,
+	"november":  time.November,
+	"december":  time.December,
+}
+
+// Parse returns the start and end minutes of the day of the range, e.g. 840 and 960 for 14:00 to 16:00.
+func (r TimeRange) Parse() (int, int, error) {
+	start, err := parseClockMinute(r.StartMinute)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := parseClockMinute(r.EndMinute)
+	if err != nil {
+		return 0, 0, err
+	}
+	if start >= end {
+		return 0, 0, fmt.Errorf("time range %s-%s must end after it starts", r.StartMinute, r.EndMinute)
+	}
+	return start, end, nil
+}
+
+func parseClockMinute(s string) (int, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 || len(parts[0]) != 2 || len(parts[1]) != 2 {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	minute := hours*60 + minutes
+	if hours < 0 || minutes < 0 || minutes > 59 || minute > 24*60 {
+		return 0, fmt.Errorf("invalid time %q, must be between 00:00 and 24:00", s)
+	}
+	return minute, nil
+}
+
+// Parse returns the first and last weekdays of the range.
+func (r WeekdayRange) Parse() (time.Weekday, time.Weekday, error) {
+	start, end, err := parseInclusiveRange(string(r), func(s string) (int, error) {
+		day, ok := weekdayNames[s]
+		if !ok {
+			return 0, fmt.Errorf("invalid weekday %q", s)
+		}
+		return int(day), nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	if start > end {
+		return 0, 0, fmt.Errorf("invalid weekday range %q, weeks start on sunday", r)
+	}
+	return time.Weekday(start), time.Weekday(end), nil
+}
+
+// Parse returns the first and last days of the range. Negative days count from the end of the month, -1 being the
+// last day.
+func (r DayOfMonthRange) Parse() (int, int, error) {
+	start, end, err := parseInclusiveRange(string(r), func(s string) (int, error) {
+		day, err := strconv.Atoi(s)
+		if err != nil || day == 0 || day < -31 || day > 31 {
+			return 0, fmt.Errorf("invalid day of month %q, must be between 1 and 31 or -31 and -1", s)
+		}
+		return day, nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	if (start < 0) == (end < 0) && start > end {
+		return 0, 0, fmt.Errorf("invalid day of month range %q, it must end after it starts", r)
+	}
+	if start < 0 && end > 0 {
+		return 0, 0, fmt.Errorf("invalid day of month range %q, a negative start requires a negative end", r)
+	}
+	return start, end, nil
+}
+
+// Parse returns the first and last months of the range.
+func (r MonthRange) Parse() (time.Month, time.Month, error) {
+	start, end, err := parseInclusiveRange(string(r), func(s string) (int, error) {
+		if month, ok := monthNames[s]; ok {
+			return int(month), nil
+		}
+		month, err := strconv.Atoi(s)
+		if err != nil || month < 1 || month > 12 {
+			return 0, fmt.Errorf("invalid month %q", s)
+		}
+		return month, nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	if start > end {
+		return 0, 0, fmt.Errorf("invalid month range %q, it must end after it starts", r)
+	}
+	return time.Month(start), time.Month(end), nil
+}
+
+// Parse returns the first and last years of the range.
+func (r YearRange) Parse() (int, int, error) {
+	start, end, err := parseInclusiveRange(string(r), func(s string) (int, error) {
+		year, err := strconv.Atoi(s)
+		if err != nil || year < 1 {
+			return 0, fmt.Errorf("invalid year %q", s)
+		}
+		return year, nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	if start > end {
+		return 0, 0, fmt.Errorf("invalid year range %q, it must end after it starts", r)
+	}
+	return start, end, nil
+}
+
+// parseInclusiveRange parses a single value or a "start:end" range, case-insensitively.
+func parseInclusiveRange(s string, parse func(string) (int, error)) (int, int, error) {
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(s)), ":")
+	if len(parts) > 2 {
+		return 0, 0, fmt.Errorf("invalid range %q", s)
+	}
+	start, err := parse(parts[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(parts) == 1 {
+		return start, start, nil
+	}
+	end, err := parse(parts[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+// Validate checks the name of the mute timing and all of its time intervals.
+func (mt *MuteTiming) Validate() error {
+	if mt.Name == "" {
+		return fmt.Errorf("mute timing name is required")
+	}
+	for i, ti := range mt.TimeIntervals {
+		if err := ti.Validate(); err != nil {
+			return fmt.Errorf("mute timing %s, time interval %d: %w", mt.Name, i, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks all the ranges and the location of the time interval.
+func (ti TimeInterval) Validate() error {
+	if _, err := ti.location(); err != nil {
+		return err
+	}
+	for _, r := range ti.Times {
+		if _, _, err := r.Parse(); err != nil {
+			return err
+		}
+	}
+	for _, r := range ti.Weekdays {
+		if _, _, err := r.Parse(); err != nil {
+			return err
+		}
+	}
+	for _, r := range ti.DaysOfMonth {
+		if _, _, err := r.Parse(); err != nil {
+			return err
+		}
+	}
+	for _, r := range ti.Months {
+		if _, _, err := r.Parse(); err != nil {
+			return err
+		}
+	}
+	for _, r := range ti.Years {
+		if _, _, err := r.Parse(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ti TimeInterval) location() (*time.Location, error) {
+	if ti.Location == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(ti.Location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid location %q: %w", ti.Location, err)
+	}
+	return loc, nil
+}
+
+// IsMuted returns true if any time interval of the mute timing contains the time, as the Alertmanager evaluates them.
+func (mt *MuteTiming) IsMuted(t time.Time) (bool, error) {
+	for _, ti := range mt.TimeIntervals {
+		ok, err := ti.Contains(t)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+// Contains returns true if the time, converted to the location of the interval, satisfies all of its ranges.
+// Empty lists of ranges are satisfied by any time.
+func (ti TimeInterval) Contains(t time.Time) (bool, error) {
+	loc, err := ti.location()
+	if err != nil {
+		return false, err
+	}
+	t = t.In(loc)
+
+	day, err := ti.containsDay(t)
+	if err != nil || !day {
+		return false, err
+	}
+	if len(ti.Times) == 0 {
+		return true, nil
+	}
+	minute := t.Hour()*60 + t.Minute()
+	for _, r := range ti.Times {
+		start, end, err := r.Parse()
+		if err != nil {
+			return false, err
+		}
+		if minute >= start && minute < end {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// containsDay returns true if the day of the local time satisfies the weekday, day of month, month and year ranges.
+func (ti TimeInterval) containsDay(t time.Time) (bool, error) {
+	if len(ti.Weekdays) > 0 {
+		ok := false
+		for _, r := range ti.Weekdays {
+			start, end, err := r.Parse()
+			if err != nil {
+				return false, err
+			}
+			ok = ok || (t.Weekday() >= start && t.Weekday() <= end)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+
+	if len(ti.DaysOfMonth) > 0 {
+		daysInMonth := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location()).Day()
+		ok := false
+		for _, r := range ti.DaysOfMonth {
+			start, end, err := r.Parse()
+			if err != nil {
+				return false, err
+			}
+			if start < 0 {
+				start += daysInMonth + 1
+			}
+			if end < 0 {
+				end += daysInMonth + 1
+			}
+			ok = ok || (t.Day() >= start && t.Day() <= end)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+
+	if len(ti.Months) > 0 {
+		ok := false
+		for _, r := range ti.Months {
+			start, end, err := r.Parse()
+			if err != nil {
+				return false, err
+			}
+			ok = ok || (t.Month() >= start && t.Month() <= end)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+
+	if len(ti.Years) > 0 {
+		ok := false
+		for _, r := range ti.Years {
+			start, end, err := r.Parse()
+			if err != nil {
+				return false, err
+			}
+			ok = ok || (t.Year() >= start && t.Year() <= end)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// MuteWindow is a period of time during which a mute timing is active, exclusive of End.
+type MuteWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+// NextMuteWindows returns up to n windows during which the mute timing is active, starting from the given time.
+// A window in progress starts at from. Overlapping and adjacent windows of the time intervals are merged, and windows
+// are searched for up to five years ahead, the last one ending at the end of the search if it's still active then.
+func (mt *MuteTiming) NextMuteWindows(from time.Time, n int) ([]MuteWindow, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("the number of mute windows can't be negative, got %d", n)
+	}
+	if err := mt.Validate(); err != nil {
+		return nil, err
+	}
+
+	locals := make([]time.Time, len(mt.TimeIntervals))
+	for i, ti := range mt.TimeIntervals {
+		loc, err := ti.location()
+		if err != nil {
+			return nil, err
+		}
+		locals[i] = from.In(loc)
+	}
+
+	done := make([]MuteWindow, 0)
+	pending := make([]MuteWindow, 0)
+	for day := 0; day < muteTimingSearchDays && len(done) < n && len(mt.TimeIntervals) > 0; day++ {
+		// Windows of the following days start at the earliest at the next local midnight of any time interval.
+		var next time.Time
+		for i, ti := range mt.TimeIntervals {
+			local := locals[i]
+			ws, err := ti.dayWindows(time.Date(local.Year(), local.Month(), local.Day()+day, 0, 0, 0, 0, local.Location()), from)
+			if err != nil {
+				return nil, err
+			}
+			pending = append(pending, ws...)
+
+			midnight := time.Date(local.Year(), local.Month(), local.Day()+day+1, 0, 0, 0, 0, local.Location())
+			if next.IsZero() || midnight.Before(next) {
+				next = midnight
+			}
+		}
+
+		pending = mergeMuteWindows(pending)
+		// Merged windows are sorted and disjoint, the ones ending before next can't grow anymore.
+		for len(pending) > 0 && pending[0].End.Before(next) {
+			done = append(done, pending[0])
+			pending = pending[1:]
+		}
+	}
+
+	done = append(done, pending...)
+	if len(done) > n {
+		done = done[:n]
+	}
+	return done, nil
+}
+
+// mergeMuteWindows sorts the windows and merges the overlapping and adjacent ones.
+func mergeMuteWindows(windows []MuteWindow) []MuteWindow {
+	sort.Slice(windows, func(i, j int) bool {
+		return windows[i].Start.Before(windows[j].Start)
+	})
+
+	merged := make([]MuteWindow, 0, len(windows))
+	for _, w := range windows {
+		if last := len(merged) - 1; last >= 0 && !w.Start.After(merged[last].End) {
+			if w.End.After(merged[last].End) {
+				merged[last].End = w.End
+			}
+			continue
+		}
+		merged = append(merged, w)
+	}
+	return merged
+}
+
+// dayWindows returns the windows of the time interval during the given local day, ignoring the time before from.
+func (ti TimeInterval) dayWindows(day, from time.Time) ([]MuteWindow, error) {
+	ok, err := ti.containsDay(day)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	ranges := [][2]int{{0, 24 * 60}}
+	if len(ti.Times) > 0 {
+		ranges = ranges[:0]
+		for _, r := range ti.Times {
+			start, end, err := r.Parse()
+			if err != nil {
+				return nil, err
+			}
+			ranges = append(ranges, [2]int{start, end})
+		}
+	}
+
+	windows := make([]MuteWindow, 0, len(ranges))
+	for _, r := range ranges {
+		w := MuteWindow{
+			Start: time.Date(day.Year(), day.Month(), day.Day(), 0, r[0], 0, 0, day.Location()),
+			End:   time.Date(day.Year(), day.Month(), day.Day(), 0, r[1], 0, 0, day.Location()),
+		}
+		if !w.End.After(from) {
+			continue
+		}
+		if w.Start.Before(from) {
+			w.Start = from
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
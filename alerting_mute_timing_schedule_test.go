@@ -0,0 +1,195 @@
+package gapi
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMuteTimingRanges(t *testing.T) {
+	t.Run("parsing valid ranges succeeds", func(t *testing.T) {
+		if start, end, err := (TimeRange{StartMinute: "14:00", EndMinute: "24:00"}).Parse(); err != nil || start != 840 || end != 1440 {
+			t.Errorf("unexpected time range: %d, %d, %v", start, end, err)
+		}
+		if start, end, err := WeekdayRange("Monday:friday").Parse(); err != nil || start != time.Monday || end != time.Friday {
+			t.Errorf("unexpected weekday range: %s, %s, %v", start, end, err)
+		}
+		if start, end, err := DayOfMonthRange("1:-1").Parse(); err != nil || start != 1 || end != -1 {
+			t.Errorf("unexpected day of month range: %d, %d, %v", start, end, err)
+		}
+		if start, end, err := MonthRange("may:12").Parse(); err != nil || start != time.May || end != time.December {
+			t.Errorf("unexpected month range: %s, %s, %v", start, end, err)
+		}
+		if start, end, err := YearRange("2030").Parse(); err != nil || start != 2030 || end != 2030 {
+			t.Errorf("unexpected year range: %d, %d, %v", start, end, err)
+		}
+	})
+
+	t.Run("validating invalid time intervals fails", func(t *testing.T) {
+		cases := map[string]TimeInterval{
+			"time format":       {Times: []TimeRange{{StartMinute: "9:00", EndMinute: "17:00"}}},
+			"time order":        {Times: []TimeRange{{StartMinute: "17:00", EndMinute: "09:00"}}},
+			"time bounds":       {Times: []TimeRange{{StartMinute: "00:00", EndMinute: "24:01"}}},
+			"weekday":           {Weekdays: []WeekdayRange{"funday"}},
+			"weekday order":     {Weekdays: []WeekdayRange{"saturday:sunday"}},
+			"day of month":      {DaysOfMonth: []DayOfMonthRange{"0"}},
+			"day of month sign": {DaysOfMonth: []DayOfMonthRange{"-5:5"}},
+			"month":             {Months: []MonthRange{"13"}},
+			"month order":       {Months: []MonthRange{"december:january"}},
+			"year":              {Years: []YearRange{"2022:2021"}},
+			"ranges":            {Years: []YearRange{"2021:2022:2023"}},
+			"location":          {Location: "Mars/Olympus_Mons"},
+		}
+		for name, ti := range cases {
+			mt := MuteTiming{Name: "invalid", TimeIntervals: []TimeInterval{ti}}
+			if err := mt.Validate(); err == nil {
+				t.Errorf("%s: expected an error", name)
+			}
+		}
+	})
+}
+
+func TestMuteTimingIsMuted(t *testing.T) {
+	mt := MuteTiming{
+		Name: "business hours",
+		TimeIntervals: []TimeInterval{
+			{
+				Times:    []TimeRange{{StartMinute: "09:00", EndMinute: "17:00"}},
+				Weekdays: []WeekdayRange{"monday:friday"},
+				Location: "America/New_York",
+			},
+			{
+				DaysOfMonth: []DayOfMonthRange{"-1"},
+				Months:      []MonthRange{"february"},
+			},
+		},
+	}
+
+	cases := []struct {
+		time  time.Time
+		muted bool
+	}{
+		// Monday 2022-10-03 09:00 in New York.
+		{time.Date(2022, 10, 3, 13, 0, 0, 0, time.UTC), true},
+		{time.Date(2022, 10, 3, 12, 59, 0, 0, time.UTC), false},
+		{time.Date(2022, 10, 3, 21, 0, 0, 0, time.UTC), false},
+		// Saturday.
+		{time.Date(2022, 10, 8, 15, 0, 0, 0, time.UTC), false},
+		// Last day of February, in UTC.
+		{time.Date(2024, 2, 29, 23, 59, 0, 0, time.UTC), true},
+		{time.Date(2024, 2, 28, 12, 0, 0, 0, time.UTC), false},
+	}
+	for _, c := range cases {
+		muted, err := mt.IsMuted(c.time)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if muted != c.muted {
+			t.Errorf("expected muted %t at %s, got %t", c.muted, c.time, muted)
+		}
+	}
+}
+
+func TestMuteTimingNextMuteWindows(t *testing.T) {
+	t.Run("listing windows succeeds", func(t *testing.T) {
+		mt := MuteTiming{
+			Name: "nights and weekends",
+			TimeIntervals: []TimeInterval{
+				{Times: []TimeRange{{StartMinute: "00:00", EndMinute: "08:00"}, {StartMinute: "20:00", EndMinute: "24:00"}}},
+				{Weekdays: []WeekdayRange{"saturday", "sunday"}},
+			},
+		}
+
+		// Friday 2022-10-07 22:00.
+		windows, err := mt.NextMuteWindows(time.Date(2022, 10, 7, 22, 0, 0, 0, time.UTC), 3)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := []MuteWindow{
+			{Start: time.Date(2022, 10, 7, 22, 0, 0, 0, time.UTC), End: time.Date(2022, 10, 10, 8, 0, 0, 0, time.UTC)},
+			{Start: time.Date(2022, 10, 10, 20, 0, 0, 0, time.UTC), End: time.Date(2022, 10, 11, 8, 0, 0, 0, time.UTC)},
+			{Start: time.Date(2022, 10, 11, 20, 0, 0, 0, time.UTC), End: time.Date(2022, 10, 12, 8, 0, 0, 0, time.UTC)},
+		}
+		if len(windows) != len(expected) {
+			t.Fatalf("expected %d windows, got %v", len(expected), windows)
+		}
+		for i := range expected {
+			if !windows[i].Start.Equal(expected[i].Start) || !windows[i].End.Equal(expected[i].End) {
+				t.Errorf("expected window %d to be %v, got %v", i, expected[i], windows[i])
+			}
+		}
+	})
+
+	t.Run("listing windows in a location succeeds", func(t *testing.T) {
+		mt := MuteTiming{
+			Name: "new year",
+			TimeIntervals: []TimeInterval{{
+				DaysOfMonth: []DayOfMonthRange{"1"},
+				Months:      []MonthRange{"january"},
+				Years:       []YearRange{"2023:2024"},
+				Location:    "Europe/Paris",
+			}},
+		}
+
+		windows, err := mt.NextMuteWindows(time.Date(2022, 10, 7, 0, 0, 0, 0, time.UTC), 5)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(windows) != 2 {
+			t.Fatalf("expected 2 windows, got %v", windows)
+		}
+		if !windows[1].Start.Equal(time.Date(2023, 12, 31, 23, 0, 0, 0, time.UTC)) || !windows[1].End.Equal(time.Date(2024, 1, 1, 23, 0, 0, 0, time.UTC)) {
+			t.Errorf("unexpected window: %v", windows[1])
+		}
+	})
+
+	t.Run("listing windows across locations succeeds", func(t *testing.T) {
+		mt := MuteTiming{
+			Name: "late evenings",
+			TimeIntervals: []TimeInterval{
+				{Times: []TimeRange{{StartMinute: "22:00", EndMinute: "24:00"}}},
+				{Times: []TimeRange{{StartMinute: "20:00", EndMinute: "22:00"}}, Location: "America/New_York"},
+			},
+		}
+
+		windows, err := mt.NextMuteWindows(time.Date(2022, 10, 7, 12, 0, 0, 0, time.UTC), 1)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(windows) != 1 || !windows[0].Start.Equal(time.Date(2022, 10, 7, 22, 0, 0, 0, time.UTC)) || !windows[0].End.Equal(time.Date(2022, 10, 8, 2, 0, 0, 0, time.UTC)) {
+			t.Errorf("unexpected windows: %v", windows)
+		}
+	})
+
+	t.Run("listing no windows succeeds", func(t *testing.T) {
+		mt := MuteTiming{Name: "always", TimeIntervals: []TimeInterval{{}}}
+
+		windows, err := mt.NextMuteWindows(time.Now(), 0)
+
+		if err != nil || len(windows) != 0 {
+			t.Errorf("unexpected windows: %v, %v", windows, err)
+		}
+	})
+
+	t.Run("listing a negative number of windows fails", func(t *testing.T) {
+		mt := MuteTiming{Name: "always", TimeIntervals: []TimeInterval{{}}}
+
+		_, err := mt.NextMuteWindows(time.Now(), -1)
+
+		if err == nil {
+			t.Error("expected an error")
+		}
+	})
+
+	t.Run("listing windows of an invalid mute timing fails", func(t *testing.T) {
+		mt := MuteTiming{Name: "invalid", TimeIntervals: []TimeInterval{{Weekdays: []WeekdayRange{"someday"}}}}
+
+		_, err := mt.NextMuteWindows(time.Now(), 1)
+
+		if err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
@@ -47,17 +47,29 @@ const (
 	MatchNotRegexp
 )
 
+var matchTypeStrings = map[MatchType]string{
+	MatchEqual:     "=",
+	MatchNotEqual:  "!=",
+	MatchRegexp:    "=~",
+	MatchNotRegexp: "!~",
+}
+
+// String returns the operator of the match type, e.g. "=~". Unknown match types are formatted as MatchType(n).
 func (m MatchType) String() string {
-	typeToStr := map[MatchType]string{
-		MatchEqual:     "=",
-		MatchNotEqual:  "!=",
-		MatchRegexp:    "=~",
-		MatchNotRegexp: "!~",
-	}
-	if str, ok := typeToStr[m]; ok {
+	if str, ok := matchTypeStrings[m]; ok {
 		return str
 	}
-	panic("unknown match type")
+	return fmt.Sprintf("MatchType(%d)", int(m))
+}
+
+// parseMatchType returns the match type of an operator, e.g. "=~".
+func parseMatchType(operator string) (MatchType, error) {
+	for matchType, str := range matchTypeStrings {
+		if str == operator {
+			return matchType, nil
+		}
+	}
+	return 0, fmt.Errorf("unsupported match type %q in matcher", operator)
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface for Matchers.
@@ -67,18 +79,9 @@ func (m *Matchers) UnmarshalJSON(data []byte) error {
 		return err
 	}
 	for _, rawMatcher := range rawMatchers {
-		var matchType MatchType
-		switch rawMatcher[1] {
-		case "=":
-			matchType = MatchEqual
-		case "!=":
-			matchType = MatchNotEqual
-		case "=~":
-			matchType = MatchRegexp
-		case "!~":
-			matchType = MatchNotRegexp
-		default:
-			return fmt.Errorf("unsupported match type %q in matcher", rawMatcher[1])
+		matchType, err := parseMatchType(rawMatcher[1])
+		if err != nil {
+			return err
 		}
 
 		matcher := Matcher{
@@ -98,6 +101,9 @@ func (m Matchers) MarshalJSON() ([]byte, error) {
 	}
 	result := make([][3]string, len(m))
 	for i, matcher := range m {
+		if _, ok := matchTypeStrings[matcher.Type]; !ok {
+			return nil, fmt.Errorf("unknown match type %d in matcher on %s", int(matcher.Type), matcher.Name)
+		}
 		result[i] = [3]string{matcher.Name, matcher.Type.String(), matcher.Value}
 	}
 	return json.Marshal(result)
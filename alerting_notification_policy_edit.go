@@ -0,0 +1,182 @@
+package gapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// notificationPolicyTreeMaxAttempts is the number of times UpdateNotificationPolicyTree tries to save the tree.
+const notificationPolicyTreeMaxAttempts = 5
+
+// errNotificationPolicyTreeChanged is returned when the tree changes while an update is applied to it.
+var errNotificationPolicyTreeChanged = errors.New("notification policy tree changed during the update")
+
+// FindPoliciesByMatchers returns the paths of the policies whose matchers are exactly the given ones,
+// regardless of their order. A path holds the indexes of the routes leading to a policy from the root.
+func (t *NotificationPolicyTree) FindPoliciesByMatchers(matchers Matchers) [][]int {
+	return findPolicies(t.Routes, nil, func(p *SpecificPolicy) bool {
+		return sameMatchers(p.ObjectMatchers, matchers)
+	})
+}
+
+// FindPoliciesByReceiver returns the paths of the policies sending notifications to the receiver.
+// Policies inheriting the receiver from their parent are not returned.
+func (t *NotificationPolicyTree) FindPoliciesByReceiver(receiver string) [][]int {
+	return findPolicies(t.Routes, nil, func(p *SpecificPolicy) bool {
+		return p.Receiver == receiver
+	})
+}
+
+// Policy returns the policy at the path, which can be modified in place.
+func (t *NotificationPolicyTree) Policy(path []int) (*SpecificPolicy, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("the root policy is not a specific policy")
+	}
+	routes, err := t.childPolicies(path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	i := path[len(path)-1]
+	if i < 0 || i >= len(*routes) {
+		return nil, fmt.Errorf("no policy at path %v", path)
+	}
+	return &(*routes)[i], nil
+}
+
+// UpsertPolicy adds the policy as a child of the policy at the parent path, or of the root policy if it's empty,
+// and returns its path. If the parent already has a child with the same matchers, it's replaced along with its
+// children. Otherwise, the policy is inserted at the index, or appended if it's negative; as the first matching policy
+// wins, its position matters.
+func (t *NotificationPolicyTree) UpsertPolicy(parentPath []int, policy SpecificPolicy, index int) ([]int, error) {
+	routes, err := t.childPolicies(parentPath)
+	if err != nil {
+		return nil, err
+	}
+
+	path := make([]int, len(parentPath), len(parentPath)+1)
+	copy(path, parentPath)
+	for i := range *routes {
+		if sameMatchers((*routes)[i].ObjectMatchers, policy.ObjectMatchers) {
+			(*routes)[i] = policy
+			return append(path, i), nil
+		}
+	}
+
+	if index < 0 || index > len(*routes) {
+		index = len(*routes)
+	}
+	*routes = append(*routes, SpecificPolicy{})
+	copy((*routes)[index+1:], (*routes)[index:])
+	(*routes)[index] = policy
+	return append(path, index), nil
+}
+
+// RemovePolicy removes the child with the matchers, along with its own children, from the policy at the parent path,
+// or from the root policy if it's empty. It returns false if there is no such child.
+func (t *NotificationPolicyTree) RemovePolicy(parentPath []int, matchers Matchers) (bool, error) {
+	routes, err := t.childPolicies(parentPath)
+	if err != nil {
+		return false, err
+	}
+
+	for i := range *routes {
+		if sameMatchers((*routes)[i].ObjectMatchers, matchers) {
+			*routes = append((*routes)[:i], (*routes)[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// childPolicies returns the children of the policy at the path, or of the root policy if it's empty.
+func (t *NotificationPolicyTree) childPolicies(path []int) (*[]SpecificPolicy, error) {
+	routes := &t.Routes
+	for depth, i := range path {
+		if i < 0 || i >= len(*routes) {
+			return nil, fmt.Errorf("no policy at path %v", path[:depth+1])
+		}
+		routes = &(*routes)[i].Routes
+	}
+	return routes, nil
+}
+
+// UpdateNotificationPolicyTree fetches the notification policy tree, applies the update to it and saves it,
+// leaving the rest of the tree untouched. The tree isn't saved if update doesn't change it.
+// As Grafana doesn't support concurrency tokens for the tree, it is fetched again right before saving, and the whole
+// operation is retried if it changed in the meantime or if Grafana reports a conflict (409), so update must be safe
+// to run several times. This leaves a small window between the second fetch and the save during which a concurrent
+// change can still be overwritten.
+func (c *Client) UpdateNotificationPolicyTree(update func(tree *NotificationPolicyTree) error) (*NotificationPolicyTree, error) {
+	var err error
+	for attempt := 0; attempt < notificationPolicyTreeMaxAttempts; attempt++ {
+		var tree *NotificationPolicyTree
+		tree, err = c.updateNotificationPolicyTree(update)
+		if err == nil {
+			return tree, nil
+		}
+		if errorStatusCode(err) != http.StatusConflict && !errors.Is(err, errNotificationPolicyTreeChanged) {
+			return nil, err
+		}
+	}
+
+	return nil, fmt.Errorf("notification policy tree still conflicting after %d attempts: %w", notificationPolicyTreeMaxAttempts, err)
+}
+
+func (c *Client) updateNotificationPolicyTree(update func(tree *NotificationPolicyTree) error) (*NotificationPolicyTree, error) {
+	tree, err := c.NotificationPolicyTree()
+	if err != nil {
+		return nil, err
+	}
+	before, err := json.Marshal(tree)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := update(&tree); err != nil {
+		return nil, err
+	}
+	after, err := json.Marshal(tree)
+	if err != nil {
+		return nil, err
+	}
+	if string(before) == string(after) {
+		return &tree, nil
+	}
+
+	latest, err := c.NotificationPolicyTree()
+	if err != nil {
+		return nil, err
+	}
+	current, err := json.Marshal(latest)
+	if err != nil {
+		return nil, err
+	}
+	if string(current) != string(before) {
+		return nil, errNotificationPolicyTreeChanged
+	}
+
+	if err := c.SetNotificationPolicyTree(&tree); err != nil {
+		return nil, err
+	}
+	return &tree, nil
+}
+
+func findPolicies(routes []SpecificPolicy, parentPath []int, match func(*SpecificPolicy) bool) [][]int {
+	paths := make([][]int, 0)
+	for i := range routes {
+		path := make([]int, len(parentPath), len(parentPath)+1)
+		copy(path, parentPath)
+		path = append(path, i)
+		if match(&routes[i]) {
+			paths = append(paths, path)
+		}
+		paths = append(paths, findPolicies(routes[i].Routes, path, match)...)
+	}
+	return paths
+}
+
+func sameMatchers(a, b Matchers) bool {
+	return len(a) == len(b) && includesMatchers(a, b) && includesMatchers(b, a)
+}
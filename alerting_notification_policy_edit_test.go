@@ -0,0 +1,216 @@
+package gapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const (
+	editPolicyTreeJSON = `
+{
+	"receiver": "default",
+	"routes": [
+		{
+			"receiver": "infra",
+			"object_matchers": [["team", "=", "infra"]],
+			"routes": [
+				{"receiver": "pager", "object_matchers": [["severity", "=", "critical"], ["env", "=", "prod"]]}
+			]
+		},
+		{"receiver": "web", "object_matchers": [["team", "=", "web"]]},
+		{"receiver": "pager", "object_matchers": [["team", "=", "db"]]}
+	]
+}
+`
+)
+
+func editPolicyTree(t *testing.T) NotificationPolicyTree {
+	tree := NotificationPolicyTree{}
+	if err := json.Unmarshal([]byte(editPolicyTreeJSON), &tree); err != nil {
+		t.Fatal(err)
+	}
+	return tree
+}
+
+func TestFindPolicies(t *testing.T) {
+	tree := editPolicyTree(t)
+
+	paths := tree.FindPoliciesByMatchers(Matchers{
+		{Type: MatchEqual, Name: "env", Value: "prod"},
+		{Type: MatchEqual, Name: "severity", Value: "critical"},
+	})
+	if !reflect.DeepEqual(paths, [][]int{{0, 0}}) {
+		t.Errorf("Unexpected paths by matchers: %v", paths)
+	}
+
+	paths = tree.FindPoliciesByReceiver("pager")
+	if !reflect.DeepEqual(paths, [][]int{{0, 0}, {2}}) {
+		t.Errorf("Unexpected paths by receiver: %v", paths)
+	}
+
+	policy, err := tree.Policy([]int{0, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy.Receiver = "oncall"
+	if tree.Routes[0].Routes[0].Receiver != "oncall" {
+		t.Error("Policy should return a pointer into the tree")
+	}
+
+	if _, err := tree.Policy([]int{0, 3}); err == nil {
+		t.Error("Expected an error for an unknown path")
+	}
+	if _, err := tree.Policy(nil); err == nil {
+		t.Error("Expected an error for the root path")
+	}
+}
+
+func TestUpsertPolicy(t *testing.T) {
+	tree := editPolicyTree(t)
+	policy := SpecificPolicy{
+		Receiver:       "infra-staging",
+		ObjectMatchers: Matchers{{Type: MatchEqual, Name: "env", Value: "staging"}},
+	}
+
+	path, err := tree.UpsertPolicy([]int{0}, policy, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(path, []int{0, 0}) || tree.Routes[0].Routes[0].Receiver != "infra-staging" || len(tree.Routes[0].Routes) != 2 {
+		t.Errorf("Policy not inserted: %v %+v", path, tree.Routes[0].Routes)
+	}
+
+	// Upserting again replaces the policy instead of adding a duplicate.
+	policy.Receiver = "infra-staging-2"
+	path, err = tree.UpsertPolicy([]int{0}, policy, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(path, []int{0, 0}) || tree.Routes[0].Routes[0].Receiver != "infra-staging-2" || len(tree.Routes[0].Routes) != 2 {
+		t.Errorf("Policy not replaced: %v %+v", path, tree.Routes[0].Routes)
+	}
+
+	path, err = tree.UpsertPolicy(nil, SpecificPolicy{Receiver: "data", ObjectMatchers: Matchers{{Type: MatchEqual, Name: "team", Value: "data"}}}, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(path, []int{3}) || len(tree.Routes) != 4 {
+		t.Errorf("Policy not appended: %v", path)
+	}
+
+	if _, err := tree.UpsertPolicy([]int{5}, policy, 0); err == nil {
+		t.Error("Expected an error for an unknown parent path")
+	}
+}
+
+func TestRemovePolicy(t *testing.T) {
+	tree := editPolicyTree(t)
+	matchers := Matchers{{Type: MatchEqual, Name: "team", Value: "web"}}
+
+	removed, err := tree.RemovePolicy(nil, matchers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !removed || len(tree.Routes) != 2 || tree.Routes[1].Receiver != "pager" {
+		t.Errorf("Policy not removed: %+v", tree.Routes)
+	}
+
+	removed, err = tree.RemovePolicy(nil, matchers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed || len(tree.Routes) != 2 {
+		t.Error("Removing a missing policy should be a no-op")
+	}
+}
+
+func TestUpdateNotificationPolicyTree(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{
+		{200, editPolicyTreeJSON},
+		{200, editPolicyTreeJSON},
+		{409, `{"message": "conflict"}`},
+		{200, editPolicyTreeJSON},
+		{200, editPolicyTreeJSON},
+		{202, ""},
+	})
+	defer server.Close()
+
+	tree, err := client.UpdateNotificationPolicyTree(func(tree *NotificationPolicyTree) error {
+		_, err := tree.RemovePolicy(nil, Matchers{{Type: MatchEqual, Name: "team", Value: "web"}})
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tree.Routes) != 2 {
+		t.Errorf("Unexpected updated tree: %+v", tree)
+	}
+
+	req := server.receivedRequests[5]
+	if req.method != "PUT" || strings.Contains(req.body, `"web"`) || !strings.Contains(req.body, `"pager"`) {
+		t.Errorf("Unexpected request: %s %s", req.method, req.body)
+	}
+}
+
+func TestUpdateNotificationPolicyTreeConcurrentChange(t *testing.T) {
+	changedTree := `{"receiver": "changed"}`
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{
+		{200, editPolicyTreeJSON},
+		{200, changedTree},
+		{200, changedTree},
+		{200, changedTree},
+		{202, ""},
+	})
+	defer server.Close()
+
+	tree, err := client.UpdateNotificationPolicyTree(func(tree *NotificationPolicyTree) error {
+		tree.GroupBy = []string{"alertname"}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tree.Receiver != "changed" {
+		t.Errorf("The update should be applied to the changed tree, got %+v", tree)
+	}
+
+	req := server.receivedRequests[4]
+	if req.method != "PUT" || !strings.Contains(req.body, `"receiver":"changed"`) {
+		t.Errorf("Unexpected request: %s %s", req.method, req.body)
+	}
+}
+
+func TestUpdateNotificationPolicyTreeUnchanged(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, editPolicyTreeJSON}})
+	defer server.Close()
+
+	_, err := client.UpdateNotificationPolicyTree(func(tree *NotificationPolicyTree) error {
+		_, err := tree.RemovePolicy(nil, Matchers{{Type: MatchEqual, Name: "team", Value: "data"}})
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(server.receivedRequests) != 1 {
+		t.Errorf("The unchanged tree should not be saved, got %d requests", len(server.receivedRequests))
+	}
+}
+
+func TestUpdateNotificationPolicyTreeConflicts(t *testing.T) {
+	calls := make([]mockServerCall, 0)
+	for i := 0; i < notificationPolicyTreeMaxAttempts; i++ {
+		calls = append(calls, mockServerCall{200, editPolicyTreeJSON}, mockServerCall{200, editPolicyTreeJSON}, mockServerCall{409, `{"message": "conflict"}`})
+	}
+	server, client := gapiTestToolsFromCalls(t, calls)
+	defer server.Close()
+
+	_, err := client.UpdateNotificationPolicyTree(func(tree *NotificationPolicyTree) error {
+		tree.Receiver = "other"
+		return nil
+	})
+	if err == nil || errorStatusCode(err) != 409 {
+		t.Errorf("Expected a conflict error, got %v", err)
+	}
+}
@@ -0,0 +1,238 @@
+package gapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Receiver represents a Grafana Alerting receiver: the contact points, or integrations, sharing the same name,
+// which are all notified when a notification policy routes an alert to that name.
+type Receiver struct {
+	Name          string
+	ContactPoints []ContactPoint
+}
+
+// ContactPoint returns the contact point of the receiver with the UID, or nil if there is none.
+func (r *Receiver) ContactPoint(uid string) *ContactPoint {
+	for i := range r.ContactPoints {
+		if r.ContactPoints[i].UID == uid {
+			return &r.ContactPoints[i]
+		}
+	}
+	return nil
+}
+
+// Receivers fetches all contact points and groups them into receivers, sorted by name.
+func (c *Client) Receivers() ([]Receiver, error) {
+	ps, err := c.ContactPoints()
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*Receiver)
+	names := make([]string, 0)
+	for _, p := range ps {
+		r, ok := byName[p.Name]
+		if !ok {
+			r = &Receiver{Name: p.Name, ContactPoints: make([]ContactPoint, 0)}
+			byName[p.Name] = r
+			names = append(names, p.Name)
+		}
+		r.ContactPoints = append(r.ContactPoints, p)
+	}
+	sort.Strings(names)
+
+	receivers := make([]Receiver, len(names))
+	for i, name := range names {
+		receivers[i] = *byName[name]
+	}
+	return receivers, nil
+}
+
+// Receiver fetches the contact points with the given name. Unlike ContactPoint, only the matching contact points
+// are returned by Grafana.
+func (c *Client) Receiver(name string) (*Receiver, error) {
+	ps, err := c.ContactPointsByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Receiver{Name: name, ContactPoints: make([]ContactPoint, 0)}
+	for _, p := range ps {
+		// Older Grafana versions ignore the name filter.
+		if p.Name == name {
+			r.ContactPoints = append(r.ContactPoints, p)
+		}
+	}
+	if len(r.ContactPoints) == 0 {
+		return nil, fmt.Errorf("receiver %s not found", name)
+	}
+	return r, nil
+}
+
+// UpsertReceiver makes the contact points named after the receiver match its contact points, and returns the
+// resulting receiver. Desired contact points are matched to existing ones by UID if they have one, or else by type;
+// matching contact points are updated if they differ, others are created, and existing contact points left unmatched
+// are deleted. Contact points are created first and deleted last so that the receiver is never left empty, and
+// changes are rolled back if any of them fails.
+func (c *Client) UpsertReceiver(r Receiver) (*Receiver, error) {
+	if r.Name == "" {
+		return nil, fmt.Errorf("receiver name is required")
+	}
+	if len(r.ContactPoints) == 0 {
+		return nil, fmt.Errorf("receiver %s requires at least one contact point", r.Name)
+	}
+
+	ps, err := c.ContactPointsByName(r.Name)
+	if err != nil {
+		return nil, err
+	}
+	existing := make([]ContactPoint, 0)
+	for _, p := range ps {
+		if p.Name == r.Name {
+			existing = append(existing, p)
+		}
+	}
+
+	plan, err := planReceiverChanges(r, existing)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Receiver{Name: r.Name, ContactPoints: make([]ContactPoint, len(r.ContactPoints))}
+	created := make([]string, 0)
+	updated := make([]ContactPoint, 0)
+	rollback := func(err error) (*Receiver, error) {
+		failures := make([]string, 0)
+		for _, uid := range created {
+			if rollbackErr := c.DeleteContactPoint(uid); rollbackErr != nil {
+				failures = append(failures, fmt.Sprintf("delete %s: %s", uid, rollbackErr))
+			}
+		}
+		for i := range updated {
+			if rollbackErr := c.UpdateContactPoint(&updated[i]); rollbackErr != nil {
+				failures = append(failures, fmt.Sprintf("restore %s: %s", updated[i].UID, rollbackErr))
+			}
+		}
+		if len(failures) > 0 {
+			return nil, fmt.Errorf("%w (rollback failed: %s)", err, strings.Join(failures, "; "))
+		}
+		return nil, err
+	}
+
+	for i, p := range r.ContactPoints {
+		p.Name = r.Name
+		if current, ok := plan.matches[i]; ok {
+			p.UID = current.UID
+		} else {
+			p.UID = ""
+			uid, err := c.NewContactPoint(&p)
+			if err != nil {
+				return rollback(err)
+			}
+			p.UID = uid
+			created = append(created, uid)
+		}
+		result.ContactPoints[i] = p
+	}
+
+	for i := range r.ContactPoints {
+		current, ok := plan.matches[i]
+		if !ok {
+			continue
+		}
+		p := result.ContactPoints[i]
+		same, err := sameContactPoint(p, current)
+		if err != nil {
+			return rollback(err)
+		}
+		if same {
+			result.ContactPoints[i] = current
+			continue
+		}
+		if err := c.UpdateContactPoint(&p); err != nil {
+			return rollback(err)
+		}
+		updated = append(updated, current)
+	}
+
+	// Deletions can't be rolled back as deleted contact points get a new UID when recreated.
+	for _, p := range plan.deletions {
+		if err := c.DeleteContactPoint(p.UID); err != nil {
+			return nil, fmt.Errorf("failed to delete contact point %s of receiver %s: %w", p.UID, r.Name, err)
+		}
+	}
+
+	return result, nil
+}
+
+// receiverChanges maps the indexes of desired contact points to the existing contact points they update,
+// and lists the existing contact points to delete.
+type receiverChanges struct {
+	matches   map[int]ContactPoint
+	deletions []ContactPoint
+}
+
+func planReceiverChanges(desired Receiver, existing []ContactPoint) (receiverChanges, error) {
+	plan := receiverChanges{matches: make(map[int]ContactPoint)}
+	used := make(map[string]bool)
+
+	for i, p := range desired.ContactPoints {
+		if p.UID == "" {
+			continue
+		}
+		found := false
+		for _, e := range existing {
+			if e.UID == p.UID {
+				if e.Type != p.Type {
+					return plan, fmt.Errorf("contact point %s has type %s, it can't be changed to %s", e.UID, e.Type, p.Type)
+				}
+				plan.matches[i] = e
+				used[e.UID] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return plan, fmt.Errorf("contact point %s is not part of receiver %s", p.UID, desired.Name)
+		}
+	}
+
+	for i, p := range desired.ContactPoints {
+		if p.UID != "" {
+			continue
+		}
+		for _, e := range existing {
+			if !used[e.UID] && e.Type == p.Type {
+				plan.matches[i] = e
+				used[e.UID] = true
+				break
+			}
+		}
+	}
+
+	for _, e := range existing {
+		if !used[e.UID] {
+			plan.deletions = append(plan.deletions, e)
+		}
+	}
+	return plan, nil
+}
+
+// sameContactPoint returns true if updating the current contact point to the desired one would be a no-op.
+func sameContactPoint(desired, current ContactPoint) (bool, error) {
+	if desired.Type != current.Type || desired.DisableResolveMessage != current.DisableResolveMessage {
+		return false, nil
+	}
+	desiredSettings, err := json.Marshal(desired.Settings)
+	if err != nil {
+		return false, err
+	}
+	currentSettings, err := json.Marshal(current.Settings)
+	if err != nil {
+		return false, err
+	}
+	return string(desiredSettings) == string(currentSettings), nil
+}
@@ -0,0 +1,171 @@
+package gapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gobs/pretty"
+)
+
+const (
+	getReceiverContactPointsJSON = `
+[
+	{"uid": "slack1", "name": "infra", "type": "slack", "settings": {"recipient": "#infra", "token": "[REDACTED]"}, "disableResolveMessage": false},
+	{"uid": "email1", "name": "infra", "type": "email", "settings": {"addresses": "ops@example.com"}, "disableResolveMessage": false},
+	{"uid": "hook1", "name": "infra", "type": "webhook", "settings": {"url": "http://hook"}, "disableResolveMessage": false}
+]
+`
+	getAllContactPointsJSON = `
+[
+	{"uid": "web1", "name": "web", "type": "email", "settings": {"addresses": "web@example.com"}},
+	{"uid": "slack1", "name": "infra", "type": "slack", "settings": {"recipient": "#infra"}},
+	{"uid": "email1", "name": "infra", "type": "email", "settings": {"addresses": "ops@example.com"}}
+]
+`
+)
+
+func TestReceivers(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, getAllContactPointsJSON}})
+	defer server.Close()
+
+	receivers, err := client.Receivers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(pretty.PrettyFormat(receivers))
+
+	if len(receivers) != 2 || receivers[0].Name != "infra" || len(receivers[0].ContactPoints) != 2 || receivers[1].Name != "web" {
+		t.Errorf("Unexpected receivers: %v", receivers)
+	}
+	if p := receivers[0].ContactPoint("email1"); p == nil || p.Type != "email" {
+		t.Errorf("Unexpected contact point: %v", p)
+	}
+	if p := receivers[0].ContactPoint("web1"); p != nil {
+		t.Errorf("Unexpected contact point: %v", p)
+	}
+}
+
+func TestReceiver(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{
+		{200, getAllContactPointsJSON},
+		{200, "[]"},
+	})
+	defer server.Close()
+
+	r, err := client.Receiver("infra")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req := server.receivedRequests[0]; req.query.Get("name") != "infra" {
+		t.Errorf("Unexpected query: %s", req.query.Encode())
+	}
+	// Contact points with another name are filtered out for older Grafana versions ignoring the name filter.
+	if len(r.ContactPoints) != 2 {
+		t.Errorf("Unexpected receiver: %v", r)
+	}
+
+	if _, err := client.Receiver("data"); err == nil {
+		t.Error("Expected an error for a missing receiver")
+	}
+}
+
+func TestUpsertReceiver(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{
+		{200, getReceiverContactPointsJSON},
+		{202, `{"uid": "pager1"}`},
+		{202, ""},
+		{204, ""},
+	})
+	defer server.Close()
+
+	r, err := client.UpsertReceiver(Receiver{
+		Name: "infra",
+		ContactPoints: []ContactPoint{
+			{Type: "slack", Settings: map[string]interface{}{"recipient": "#infra", "token": "[REDACTED]"}},
+			{Type: "email", Settings: map[string]interface{}{"addresses": "oncall@example.com"}},
+			{Type: "pagerduty", Settings: map[string]interface{}{"integrationKey": "key"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(server.receivedRequests) != 4 {
+		t.Fatalf("Unexpected number of requests: %d", len(server.receivedRequests))
+	}
+
+	create := server.receivedRequests[1]
+	created := ContactPoint{}
+	if err := json.Unmarshal([]byte(create.body), &created); err != nil {
+		t.Fatal(err)
+	}
+	if create.method != "POST" || created.Name != "infra" || created.Type != "pagerduty" {
+		t.Errorf("Unexpected creation: %s %s", create.method, create.body)
+	}
+
+	// The unchanged Slack contact point is not updated.
+	update := server.receivedRequests[2]
+	if update.method != "PUT" || update.path != "/api/v1/provisioning/contact-points/email1" {
+		t.Errorf("Unexpected update: %s %s", update.method, update.path)
+	}
+
+	del := server.receivedRequests[3]
+	if del.method != "DELETE" || del.path != "/api/v1/provisioning/contact-points/hook1" {
+		t.Errorf("Unexpected deletion: %s %s", del.method, del.path)
+	}
+
+	uids := []string{"slack1", "email1", "pager1"}
+	for i, p := range r.ContactPoints {
+		if p.UID != uids[i] || p.Name != "infra" {
+			t.Errorf("Unexpected contact point %d: %v", i, p)
+		}
+	}
+}
+
+func TestUpsertReceiverRollback(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{
+		{200, getReceiverContactPointsJSON},
+		{202, `{"uid": "pager1"}`},
+		{400, `{"message": "invalid settings"}`},
+		{204, ""},
+	})
+	defer server.Close()
+
+	_, err := client.UpsertReceiver(Receiver{
+		Name: "infra",
+		ContactPoints: []ContactPoint{
+			{UID: "hook1", Type: "webhook", Settings: map[string]interface{}{"url": "invalid"}},
+			{Type: "pagerduty", Settings: map[string]interface{}{"integrationKey": "key"}},
+		},
+	})
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+
+	update := server.receivedRequests[2]
+	if update.method != "PUT" || update.path != "/api/v1/provisioning/contact-points/hook1" {
+		t.Errorf("Unexpected update: %s %s", update.method, update.path)
+	}
+	rollback := server.receivedRequests[3]
+	if rollback.method != "DELETE" || rollback.path != "/api/v1/provisioning/contact-points/pager1" {
+		t.Errorf("Unexpected rollback: %s %s", rollback.method, rollback.path)
+	}
+}
+
+func TestUpsertReceiverInvalid(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{
+		{200, getReceiverContactPointsJSON},
+		{200, getReceiverContactPointsJSON},
+	})
+	defer server.Close()
+
+	if _, err := client.UpsertReceiver(Receiver{Name: "infra"}); err == nil {
+		t.Error("Expected an error for a receiver without contact points")
+	}
+	if _, err := client.UpsertReceiver(Receiver{Name: "infra", ContactPoints: []ContactPoint{{UID: "other", Type: "slack"}}}); err == nil {
+		t.Error("Expected an error for a contact point of another receiver")
+	}
+	if _, err := client.UpsertReceiver(Receiver{Name: "infra", ContactPoints: []ContactPoint{{UID: "slack1", Type: "email"}}}); err == nil {
+		t.Error("Expected an error when changing the type of a contact point")
+	}
+}
@@ -0,0 +1,110 @@
+package gapi
+
+// Default notification timings of the Alertmanager, used when the root policy doesn't define them.
+const (
+	DefaultGroupWait      = "30s"
+	DefaultGroupInterval  = "5m"
+	DefaultRepeatInterval = "4h"
+)
+
+// GroupByAll is the special group_by label grouping alerts by all of their labels.
+const GroupByAll = "..."
+
+// RouteMatch represents a notification policy matching an alert, along with its effective settings,
+// including the ones inherited from its parents.
+type RouteMatch struct {
+	Receiver       string
+	GroupBy        []string
+	GroupWait      string
+	GroupInterval  string
+	RepeatInterval string
+	// MuteTimeIntervals are not inherited, they only come from the matching policy itself.
+	MuteTimeIntervals []string
+	// Path holds the indexes of the routes leading to the matching policy, it's empty for the root policy.
+	Path []int
+}
+
+// RouteAlert returns the notification policies matching an alert with the given labels, using the same semantics
+// as the Alertmanager: the children of a matching policy are evaluated in order, and evaluation stops at the first
+// matching child unless it has Continue set. A matching policy without matching children handles the alert itself.
+func (t *NotificationPolicyTree) RouteAlert(labels map[string]string) ([]RouteMatch, error) {
+	root := RouteMatch{
+		Receiver:       t.Receiver,
+		GroupBy:        t.GroupBy,
+		GroupWait:      defaultString(t.GroupWait, DefaultGroupWait),
+		GroupInterval:  defaultString(t.GroupInterval, DefaultGroupInterval),
+		RepeatInterval: defaultString(t.RepeatInterval, DefaultRepeatInterval),
+		Path:           []int{},
+	}
+
+	matches, err := routeAlert(t.Routes, root, labels)
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		matches = append(matches, root)
+	}
+	return matches, nil
+}
+
+// routeAlert returns the routes matching the labels, with their settings inherited from the parent match.
+func routeAlert(routes []SpecificPolicy, parent RouteMatch, labels map[string]string) ([]RouteMatch, error) {
+	matches := make([]RouteMatch, 0)
+	for i, route := range routes {
+		ok, err := route.ObjectMatchers.Matches(labels)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		path := make([]int, len(parent.Path), len(parent.Path)+1)
+		copy(path, parent.Path)
+		match := RouteMatch{
+			Receiver:          defaultString(route.Receiver, parent.Receiver),
+			GroupBy:           parent.GroupBy,
+			GroupWait:         defaultString(route.GroupWait, parent.GroupWait),
+			GroupInterval:     defaultString(route.GroupInterval, parent.GroupInterval),
+			RepeatInterval:    defaultString(route.RepeatInterval, parent.RepeatInterval),
+			MuteTimeIntervals: route.MuteTimeIntervals,
+			Path:              append(path, i),
+		}
+		if route.GroupBy != nil {
+			match.GroupBy = route.GroupBy
+		}
+
+		children, err := routeAlert(route.Routes, match, labels)
+		if err != nil {
+			return nil, err
+		}
+		if len(children) == 0 {
+			matches = append(matches, match)
+		} else {
+			matches = append(matches, children...)
+		}
+
+		if !route.Continue {
+			break
+		}
+	}
+	return matches, nil
+}
+
+// GroupLabels returns the labels by which the matching policy groups an alert with the given labels into
+// notifications. GroupByAll groups by all the labels of the alert, and labels missing from the alert are left out.
+func (m RouteMatch) GroupLabels(labels map[string]string) map[string]string {
+	group := make(map[string]string)
+	if containsString(m.GroupBy, GroupByAll) {
+		for name, value := range labels {
+			group[name] = value
+		}
+		return group
+	}
+	for _, name := range m.GroupBy {
+		if value, ok := labels[name]; ok {
+			group[name] = value
+		}
+	}
+	return group
+}
@@ -0,0 +1,143 @@
+package gapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const (
+	routingPolicyTreeJSON = `
+{
+	"receiver": "default",
+	"group_by": ["alertname"],
+	"group_wait": "10s",
+	"routes": [
+		{
+			"receiver": "infra",
+			"object_matchers": [["team", "=", "infra"]],
+			"group_by": ["alertname", "cluster"],
+			"repeat_interval": "1h",
+			"continue": true,
+			"routes": [
+				{"receiver": "infra-pager", "object_matchers": [["severity", "=~", "critical|page"]], "mute_time_intervals": ["nights"]},
+				{"object_matchers": [["severity", "!~", "info|debug"]], "group_wait": "1m"}
+			]
+		},
+		{"receiver": "audit", "object_matchers": [["team", "=~", "infra|web"], ["env", "!=", "dev"]]},
+		{"receiver": "never", "object_matchers": [["team", "=~", "infra"]]}
+	]
+}
+`
+)
+
+func TestRouteAlert(t *testing.T) {
+	tree := NotificationPolicyTree{}
+	if err := json.Unmarshal([]byte(routingPolicyTreeJSON), &tree); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name     string
+		labels   map[string]string
+		expected []RouteMatch
+	}{
+		{
+			name:   "root fallback",
+			labels: map[string]string{"team": "data"},
+			expected: []RouteMatch{
+				{Receiver: "default", GroupBy: []string{"alertname"}, GroupWait: "10s", GroupInterval: "5m", RepeatInterval: "4h", Path: []int{}},
+			},
+		},
+		{
+			name:   "continue into sibling",
+			labels: map[string]string{"team": "infra", "severity": "critical", "env": "prod"},
+			expected: []RouteMatch{
+				{Receiver: "infra-pager", GroupBy: []string{"alertname", "cluster"}, GroupWait: "10s", GroupInterval: "5m", RepeatInterval: "1h", MuteTimeIntervals: []string{"nights"}, Path: []int{0, 0}},
+				{Receiver: "audit", GroupBy: []string{"alertname"}, GroupWait: "10s", GroupInterval: "5m", RepeatInterval: "4h", Path: []int{1}},
+			},
+		},
+		{
+			name:   "inherited receiver and regex anchoring",
+			labels: map[string]string{"team": "infra", "severity": "warning-info", "env": "dev"},
+			expected: []RouteMatch{
+				{Receiver: "infra", GroupBy: []string{"alertname", "cluster"}, GroupWait: "1m", GroupInterval: "5m", RepeatInterval: "1h", Path: []int{0, 1}},
+				{Receiver: "never", GroupBy: []string{"alertname"}, GroupWait: "10s", GroupInterval: "5m", RepeatInterval: "4h", Path: []int{2}},
+			},
+		},
+		{
+			name:   "parent handles alert without matching child",
+			labels: map[string]string{"team": "infra", "severity": "info", "env": "dev"},
+			expected: []RouteMatch{
+				{Receiver: "infra", GroupBy: []string{"alertname", "cluster"}, GroupWait: "10s", GroupInterval: "5m", RepeatInterval: "1h", Path: []int{0}},
+				{Receiver: "never", GroupBy: []string{"alertname"}, GroupWait: "10s", GroupInterval: "5m", RepeatInterval: "4h", Path: []int{2}},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			matches, err := tree.RouteAlert(c.labels)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(matches, c.expected) {
+				t.Errorf("Unexpected matches:\n%+v\nexpected:\n%+v", matches, c.expected)
+			}
+		})
+	}
+}
+
+func TestRouteAlertInvalidRegexp(t *testing.T) {
+	tree := NotificationPolicyTree{
+		Receiver: "default",
+		Routes: []SpecificPolicy{
+			{Receiver: "broken", ObjectMatchers: Matchers{{Type: MatchRegexp, Name: "team", Value: "(infra"}}},
+		},
+	}
+	if _, err := tree.RouteAlert(map[string]string{"team": "infra"}); err == nil {
+		t.Error("Expected an error for an invalid regular expression")
+	}
+}
+
+func TestRouteMatchGroupLabels(t *testing.T) {
+	tree := NotificationPolicyTree{
+		Receiver: "default",
+		GroupBy:  []string{"alertname", "cluster"},
+		Routes: []SpecificPolicy{
+			{Receiver: "all", ObjectMatchers: Matchers{{Type: MatchEqual, Name: "team", Value: "infra"}}, GroupBy: []string{GroupByAll}},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		labels   map[string]string
+		expected map[string]string
+	}{
+		{
+			name:     "group by labels",
+			labels:   map[string]string{"alertname": "HighCPU", "team": "web"},
+			expected: map[string]string{"alertname": "HighCPU"},
+		},
+		{
+			name:     "group by all labels",
+			labels:   map[string]string{"alertname": "HighCPU", "team": "infra", "instance": "a"},
+			expected: map[string]string{"alertname": "HighCPU", "team": "infra", "instance": "a"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			matches, err := tree.RouteAlert(c.labels)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(matches) != 1 {
+				t.Fatalf("Expected a single match, got %+v", matches)
+			}
+			if group := matches[0].GroupLabels(c.labels); !reflect.DeepEqual(group, c.expected) {
+				t.Errorf("Unexpected group labels: %v, expected %v", group, c.expected)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package gapi
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -128,13 +129,19 @@ func (c *Client) request(method, requestPath string, query url.Values, body io.R
 
 	// check status code.
 	if resp.StatusCode >= 400 {
-		return fmt.Errorf("status: %d, body: %v", resp.StatusCode, string(bodyContents))
+		return &requestError{StatusCode: resp.StatusCode, Body: bodyContents}
 	}
 
 	if responseStruct == nil {
 		return nil
 	}
 
+	// Non-JSON responses, e.g. exports in YAML format, can be fetched as is.
+	if raw, ok := responseStruct.(*[]byte); ok {
+		*raw = bodyContents
+		return nil
+	}
+
 	err = json.Unmarshal(bodyContents, responseStruct)
 	if err != nil {
 		return err
@@ -143,6 +150,25 @@ func (c *Client) request(method, requestPath string, query url.Values, body io.R
 	return nil
 }
 
+// requestError is returned by request when the API responds with an error status code.
+type requestError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *requestError) Error() string {
+	return fmt.Sprintf("status: %d, body: %v", e.StatusCode, string(e.Body))
+}
+
+// errorStatusCode returns the status code of the API error wrapped in err, or 0 if there is none.
+func errorStatusCode(err error) int {
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		return reqErr.StatusCode
+	}
+	return 0
+}
+
 func (c *Client) newRequest(method, requestPath string, query url.Values, body io.Reader) (*http.Request, error) {
 	url := c.baseURL
 	url.Path = path.Join(url.Path, requestPath)
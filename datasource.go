@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"reflect"
 )
 
 // DataSource represents a Grafana data source.
@@ -33,8 +35,21 @@ type DataSource struct {
 
 	JSONData       map[string]interface{} `json:"jsonData,omitempty"`
 	SecureJSONData map[string]interface{} `json:"secureJsonData,omitempty"`
+
+	// SecureJSONFields tells which secure JSON data keys have a value, as secure values are never returned.
+	// Grafana fills it in from the stored secure values and ignores it on updates.
+	SecureJSONFields map[string]bool `json:"secureJsonFields,omitempty"`
 }
 
+// DataSourceUpsertResult describes what UpsertDataSource did.
+type DataSourceUpsertResult string
+
+const (
+	DataSourceCreated   DataSourceUpsertResult = "created"
+	DataSourceUpdated   DataSourceUpsertResult = "updated"
+	DataSourceUnchanged DataSourceUpsertResult = "unchanged"
+)
+
 // NewDataSource creates a new Grafana data source.
 func (c *Client) NewDataSource(s *DataSource) (int64, error) {
 	data, err := json.Marshal(s)
@@ -55,6 +70,8 @@ func (c *Client) NewDataSource(s *DataSource) (int64, error) {
 }
 
 // UpdateDataSource updates a Grafana data source.
+// Secure JSON data keys which are not supplied keep their current value, an empty value resets them.
+// Every other property is replaced, including JSON data.
 func (c *Client) UpdateDataSource(s *DataSource) error {
 	path := fmt.Sprintf("/api/datasources/%d", s.ID)
 	data, err := json.Marshal(s)
@@ -65,6 +82,8 @@ func (c *Client) UpdateDataSource(s *DataSource) error {
 	return c.request("PUT", path, nil, bytes.NewBuffer(data), nil)
 }
 
+// UpdateDataSourceByUID updates a Grafana data source, using its UID instead of its ID.
+// Secure JSON data is merged as with UpdateDataSource.
 func (c *Client) UpdateDataSourceByUID(s *DataSource) error {
 	path := fmt.Sprintf("/api/datasources/uid/%s", s.UID)
 	data, err := json.Marshal(s)
@@ -75,6 +94,22 @@ func (c *Client) UpdateDataSourceByUID(s *DataSource) error {
 	return c.request("PUT", path, nil, bytes.NewBuffer(data), nil)
 }
 
+// ResetDataSourceSecureFields clears the given secure JSON data keys of the data source whose UID it's passed,
+// leaving its other properties and secure JSON data keys untouched.
+func (c *Client) ResetDataSourceSecureFields(uid string, keys ...string) error {
+	ds, err := c.DataSourceByUID(uid)
+	if err != nil {
+		return err
+	}
+
+	ds.SecureJSONData = make(map[string]interface{}, len(keys))
+	for _, key := range keys {
+		ds.SecureJSONData[key] = ""
+	}
+
+	return c.UpdateDataSourceByUID(ds)
+}
+
 // DataSource fetches and returns the Grafana data source whose ID it's passed.
 func (c *Client) DataSource(id int64) (*DataSource, error) {
 	path := fmt.Sprintf("/api/datasources/%d", id)
@@ -99,6 +134,18 @@ func (c *Client) DataSourceByUID(uid string) (*DataSource, error) {
 	return result, err
 }
 
+// DataSourceByName fetches and returns the Grafana data source whose name is passed.
+func (c *Client) DataSourceByName(name string) (*DataSource, error) {
+	path := fmt.Sprintf("/api/datasources/name/%s", name)
+	result := &DataSource{}
+	err := c.request("GET", path, nil, nil, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, err
+}
+
 // DataSourceIDByName returns the Grafana data source ID by name.
 func (c *Client) DataSourceIDByName(name string) (int64, error) {
 	path := fmt.Sprintf("/api/datasources/id/%s", name)
@@ -132,3 +179,95 @@ func (c *Client) DeleteDataSource(id int64) error {
 
 	return c.request("DELETE", path, nil, nil, nil)
 }
+
+// UpsertDataSource creates the Grafana data source or updates the existing one matching its UID or, failing that, its name.
+// On success, the ID and UID of s are set to the ones of the created or updated data source.
+//
+// Secure JSON data fields which are not supplied keep their current value. As secure values can't be read back,
+// a data source is only reported unchanged when no secure JSON data is supplied and every other property matches.
+func (c *Client) UpsertDataSource(s *DataSource) (DataSourceUpsertResult, error) {
+	existing, err := c.matchingDataSource(s)
+	if err != nil {
+		return "", err
+	}
+
+	if existing == nil {
+		id, err := c.NewDataSource(s)
+		if err != nil {
+			return "", err
+		}
+		s.ID = id
+		return DataSourceCreated, nil
+	}
+
+	s.ID = existing.ID
+	s.UID = existing.UID
+	if s.OrgID == 0 {
+		s.OrgID = existing.OrgID
+	}
+	s.ReadOnly = existing.ReadOnly
+	s.SecureJSONFields = existing.SecureJSONFields
+
+	unchanged, err := sameDataSource(existing, s)
+	if err != nil {
+		return "", err
+	}
+	if unchanged {
+		return DataSourceUnchanged, nil
+	}
+
+	if err := c.UpdateDataSource(s); err != nil {
+		return "", err
+	}
+	return DataSourceUpdated, nil
+}
+
+// matchingDataSource returns the data source matching the UID or name of s, or nil if there is none.
+func (c *Client) matchingDataSource(s *DataSource) (*DataSource, error) {
+	if s.UID != "" {
+		existing, err := c.DataSourceByUID(s.UID)
+		if err == nil {
+			return existing, nil
+		}
+		if errorStatusCode(err) != http.StatusNotFound {
+			return nil, err
+		}
+	}
+
+	existing, err := c.DataSourceByName(s.Name)
+	if err == nil {
+		return existing, nil
+	}
+	if errorStatusCode(err) != http.StatusNotFound {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// sameDataSource returns true if updating the existing data source with the desired one would not change anything.
+func sameDataSource(existing, desired *DataSource) (bool, error) {
+	if len(desired.SecureJSONData) > 0 || desired.Password != "" || desired.BasicAuthPassword != "" {
+		return false, nil
+	}
+
+	// Compare the JSON representations so that numbers decoded from the API match the ones set in Go.
+	existingData, err := json.Marshal(existing)
+	if err != nil {
+		return false, err
+	}
+	desiredData, err := json.Marshal(desired)
+	if err != nil {
+		return false, err
+	}
+
+	var existingFields, desiredFields map[string]interface{}
+	if err := json.Unmarshal(existingData, &existingFields); err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(desiredData, &desiredFields); err != nil {
+		return false, err
+	}
+
+	return reflect.DeepEqual(existingFields, desiredFields), nil
+}
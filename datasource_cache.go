@@ -0,0 +1,93 @@
+package gapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// DataSourceCacheConfig represents the query caching configuration of a Grafana data source.
+// Query caching is only available in Grafana Enterprise and Grafana Cloud.
+type DataSourceCacheConfig struct {
+	DataSourceID  int64  `json:"dataSourceID"`
+	DataSourceUID string `json:"dataSourceUID"`
+	Enabled       bool   `json:"enabled"`
+	// UseDefaultTTL makes the data source use the TTL configured on the Grafana server instead of its own.
+	UseDefaultTTL bool `json:"useDefaultTTL"`
+	// TTLQueriesMs is the time to live of cached query results, in milliseconds.
+	TTLQueriesMs int64 `json:"ttlQueriesMs"`
+	// TTLResourcesMs is the time to live of cached resource calls, in milliseconds.
+	TTLResourcesMs int64 `json:"ttlResourcesMs"`
+	// DefaultTTLMs is the TTL configured on the Grafana server, used when UseDefaultTTL is set.
+	// It's read-only, like the Created and Updated timestamps Grafana sets when the configuration is saved.
+	DefaultTTLMs int64  `json:"defaultTTLMs,omitempty"`
+	Created      string `json:"created,omitempty"`
+	Updated      string `json:"updated,omitempty"`
+}
+
+// DataSourceCache fetches and returns the query caching configuration of the data source whose UID it's passed.
+func (c *Client) DataSourceCache(uid string) (*DataSourceCacheConfig, error) {
+	config := &DataSourceCacheConfig{}
+	err := c.request("GET", fmt.Sprintf("/api/datasources/%s/cache", uid), nil, nil, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// UpdateDataSourceCache updates the query caching configuration of the data source whose UID it's passed.
+func (c *Client) UpdateDataSourceCache(uid string, config DataSourceCacheConfig) (*DataSourceCacheConfig, error) {
+	payload := struct {
+		DataSourceID   int64  `json:"dataSourceID,omitempty"`
+		DataSourceUID  string `json:"dataSourceUID"`
+		Enabled        bool   `json:"enabled"`
+		UseDefaultTTL  bool   `json:"useDefaultTTL"`
+		TTLQueriesMs   int64  `json:"ttlQueriesMs"`
+		TTLResourcesMs int64  `json:"ttlResourcesMs"`
+	}{
+		DataSourceID:   config.DataSourceID,
+		DataSourceUID:  uid,
+		Enabled:        config.Enabled,
+		UseDefaultTTL:  config.UseDefaultTTL,
+		TTLQueriesMs:   config.TTLQueriesMs,
+		TTLResourcesMs: config.TTLResourcesMs,
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DataSourceCacheConfig{}
+	err = c.request("POST", fmt.Sprintf("/api/datasources/%s/cache", uid), nil, bytes.NewBuffer(data), result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// EnableDataSourceCache enables query caching for the data source whose UID it's passed.
+func (c *Client) EnableDataSourceCache(uid string) (*DataSourceCacheConfig, error) {
+	return c.dataSourceCacheAction(uid, "enable")
+}
+
+// DisableDataSourceCache disables query caching for the data source whose UID it's passed.
+func (c *Client) DisableDataSourceCache(uid string) (*DataSourceCacheConfig, error) {
+	return c.dataSourceCacheAction(uid, "disable")
+}
+
+// CleanDataSourceCache removes every cached query result and resource call of the data source whose UID it's passed.
+func (c *Client) CleanDataSourceCache(uid string) (*DataSourceCacheConfig, error) {
+	return c.dataSourceCacheAction(uid, "clean")
+}
+
+func (c *Client) dataSourceCacheAction(uid, action string) (*DataSourceCacheConfig, error) {
+	config := &DataSourceCacheConfig{}
+	err := c.request("POST", fmt.Sprintf("/api/datasources/%s/cache/%s", uid, action), nil, nil, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
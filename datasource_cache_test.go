@@ -0,0 +1,89 @@
+package gapi
+
+import (
+	"testing"
+
+	"github.com/gobs/pretty"
+)
+
+const (
+	dataSourceCacheJSON = `
+{
+  "message": "Data source cache settings loaded",
+  "dataSourceID": 1,
+  "dataSourceUID": "prom",
+  "enabled": true,
+  "useDefaultTTL": false,
+  "ttlQueriesMs": 300000,
+  "ttlResourcesMs": 600000,
+  "defaultTTLMs": 300000,
+  "created": "2022-08-01T12:00:00Z",
+  "updated": "2022-08-02T12:00:00Z"
+}
+`
+)
+
+func TestDataSourceCache(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, dataSourceCacheJSON}})
+	defer server.Close()
+
+	config, err := client.DataSourceCache("prom")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(pretty.PrettyFormat(config))
+
+	if !config.Enabled || config.TTLQueriesMs != 300000 || config.TTLResourcesMs != 600000 || config.DataSourceUID != "prom" {
+		t.Error("Not correctly parsing returned cache configuration.")
+	}
+	if path := server.receivedRequests[0].path; path != "/api/datasources/prom/cache" {
+		t.Errorf("Unexpected request path: %s", path)
+	}
+}
+
+func TestUpdateDataSourceCache(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, dataSourceCacheJSON}})
+	defer server.Close()
+
+	_, err := client.UpdateDataSourceCache("prom", DataSourceCacheConfig{
+		Enabled:        true,
+		TTLQueriesMs:   300000,
+		TTLResourcesMs: 600000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := server.receivedRequests[0]
+	expected := `{"dataSourceUID":"prom","enabled":true,"useDefaultTTL":false,"ttlQueriesMs":300000,"ttlResourcesMs":600000}`
+	if req.method != "POST" || req.body != expected {
+		t.Errorf("Unexpected request: %s %s", req.method, req.body)
+	}
+}
+
+func TestDataSourceCacheActions(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{
+		{200, dataSourceCacheJSON},
+		{200, dataSourceCacheJSON},
+		{200, dataSourceCacheJSON},
+	})
+	defer server.Close()
+
+	if _, err := client.EnableDataSourceCache("prom"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.DisableDataSourceCache("prom"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CleanDataSourceCache("prom"); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, action := range []string{"enable", "disable", "clean"} {
+		req := server.receivedRequests[i]
+		if req.method != "POST" || req.path != "/api/datasources/prom/cache/"+action {
+			t.Errorf("Unexpected request: %s %s", req.method, req.path)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package gapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Correlation transformation types.
+const (
+	CorrelationTransformationRegex  = "regex"
+	CorrelationTransformationLogfmt = "logfmt"
+)
+
+// Correlation represents a Grafana correlation, linking the results of a source data source to queries
+// against a target data source. Available from Grafana 10.
+type Correlation struct {
+	UID         string            `json:"uid,omitempty"`
+	OrgID       int64             `json:"orgId,omitempty"`
+	SourceUID   string            `json:"sourceUID"`
+	TargetUID   string            `json:"targetUID,omitempty"`
+	Label       string            `json:"label"`
+	Description string            `json:"description,omitempty"`
+	Config      CorrelationConfig `json:"config"`
+
+	// Provisioned is set by Grafana for correlations defined in provisioning files, and ignored on updates.
+	Provisioned bool `json:"provisioned,omitempty"`
+}
+
+// CorrelationConfig represents the query a correlation runs against its target data source.
+type CorrelationConfig struct {
+	// Type of the correlation, only "query" is supported.
+	Type string `json:"type"`
+	// Field of the source results the correlation link is attached to.
+	Field string `json:"field"`
+	// Target is the query run against the target data source. It may reference variables extracted
+	// by the transformations, e.g. {"query": "${traceId}"} for a Tempo data source.
+	Target          map[string]interface{}      `json:"target"`
+	Transformations []CorrelationTransformation `json:"transformations,omitempty"`
+}
+
+// CorrelationTransformation extracts variables from a field of the source results.
+type CorrelationTransformation struct {
+	// Type is either CorrelationTransformationRegex or CorrelationTransformationLogfmt.
+	Type string `json:"type"`
+	// Field to extract from, defaults to the field of the correlation.
+	Field string `json:"field,omitempty"`
+	// Expression is the regular expression used by regex transformations.
+	Expression string `json:"expression,omitempty"`
+	// MapValue is the name of the variable holding the value extracted by regex transformations.
+	MapValue string `json:"mapValue,omitempty"`
+}
+
+// DataSourceCorrelations fetches and returns the correlations of the source data source whose UID it's passed.
+func (c *Client) DataSourceCorrelations(sourceUID string) ([]Correlation, error) {
+	correlations := make([]Correlation, 0)
+	err := c.request("GET", fmt.Sprintf("/api/datasources/uid/%s/correlations", sourceUID), nil, nil, &correlations)
+	if err != nil {
+		return nil, err
+	}
+
+	return correlations, nil
+}
+
+// DataSourceCorrelation fetches and returns the correlation whose source data source UID and UID it's passed.
+func (c *Client) DataSourceCorrelation(sourceUID, uid string) (*Correlation, error) {
+	correlation := &Correlation{}
+	err := c.request("GET", fmt.Sprintf("/api/datasources/uid/%s/correlations/%s", sourceUID, uid), nil, nil, correlation)
+	if err != nil {
+		return nil, err
+	}
+
+	return correlation, nil
+}
+
+// NewDataSourceCorrelation creates a new correlation from its source data source.
+func (c *Client) NewDataSourceCorrelation(correlation Correlation) (*Correlation, error) {
+	data, err := json.Marshal(correlation)
+	if err != nil {
+		return nil, err
+	}
+
+	result := struct {
+		Result Correlation `json:"result"`
+	}{}
+	path := fmt.Sprintf("/api/datasources/uid/%s/correlations", correlation.SourceUID)
+	err = c.request("POST", path, nil, bytes.NewBuffer(data), &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result.Result, nil
+}
+
+// UpdateDataSourceCorrelation updates the label, description and config of the correlation matching the UID and
+// source data source UID of the one it's passed. Its target data source can't be changed.
+func (c *Client) UpdateDataSourceCorrelation(correlation Correlation) (*Correlation, error) {
+	payload := struct {
+		Label       string            `json:"label"`
+		Description string            `json:"description"`
+		Config      CorrelationConfig `json:"config"`
+	}{
+		Label:       correlation.Label,
+		Description: correlation.Description,
+		Config:      correlation.Config,
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	result := struct {
+		Result Correlation `json:"result"`
+	}{}
+	path := fmt.Sprintf("/api/datasources/uid/%s/correlations/%s", correlation.SourceUID, correlation.UID)
+	err = c.request("PATCH", path, nil, bytes.NewBuffer(data), &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result.Result, nil
+}
+
+// DeleteDataSourceCorrelation deletes the correlation whose source data source UID and UID it's passed.
+func (c *Client) DeleteDataSourceCorrelation(sourceUID, uid string) error {
+	return c.request("DELETE", fmt.Sprintf("/api/datasources/uid/%s/correlations/%s", sourceUID, uid), nil, nil, nil)
+}
@@ -0,0 +1,136 @@
+package gapi
+
+import (
+	"testing"
+
+	"github.com/gobs/pretty"
+)
+
+const (
+	correlationJSON = `
+{
+  "uid": "vJkJfYeVz",
+  "orgId": 1,
+  "sourceUID": "loki",
+  "targetUID": "tempo",
+  "label": "Trace",
+  "description": "Open the trace of this log line",
+  "config": {
+    "type": "query",
+    "field": "message",
+    "target": {"query": "${traceId}"},
+    "transformations": [
+      {"type": "regex", "field": "message", "expression": "traceID=(\\w+)", "mapValue": "traceId"}
+    ]
+  },
+  "provisioned": false
+}
+`
+	correlationResultJSON = `{"message": "Correlation created", "result": ` + correlationJSON + `}`
+	correlationsJSON      = `[` + correlationJSON + `]`
+)
+
+func lokiToTempoCorrelation() Correlation {
+	return Correlation{
+		SourceUID:   "loki",
+		TargetUID:   "tempo",
+		Label:       "Trace",
+		Description: "Open the trace of this log line",
+		Config: CorrelationConfig{
+			Type:   "query",
+			Field:  "message",
+			Target: map[string]interface{}{"query": "${traceId}"},
+			Transformations: []CorrelationTransformation{
+				{Type: CorrelationTransformationRegex, Field: "message", Expression: `traceID=(\w+)`, MapValue: "traceId"},
+			},
+		},
+	}
+}
+
+func TestDataSourceCorrelations(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, correlationsJSON}})
+	defer server.Close()
+
+	correlations, err := client.DataSourceCorrelations("loki")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(pretty.PrettyFormat(correlations))
+
+	if len(correlations) != 1 {
+		t.Fatal("Length of returned correlations should be 1")
+	}
+	correlation := correlations[0]
+	if correlation.UID != "vJkJfYeVz" || correlation.TargetUID != "tempo" || correlation.Config.Transformations[0].MapValue != "traceId" {
+		t.Error("Not correctly parsing returned correlations.")
+	}
+	if path := server.receivedRequests[0].path; path != "/api/datasources/uid/loki/correlations" {
+		t.Errorf("Unexpected request path: %s", path)
+	}
+}
+
+func TestDataSourceCorrelation(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, correlationJSON}})
+	defer server.Close()
+
+	correlation, err := client.DataSourceCorrelation("loki", "vJkJfYeVz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if correlation.Config.Target["query"] != "${traceId}" {
+		t.Error("Not correctly parsing returned correlation.")
+	}
+}
+
+func TestNewDataSourceCorrelation(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, correlationResultJSON}})
+	defer server.Close()
+
+	correlation, err := client.NewDataSourceCorrelation(lokiToTempoCorrelation())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if correlation.UID != "vJkJfYeVz" {
+		t.Error("Not correctly parsing returned correlation.")
+	}
+
+	req := server.receivedRequests[0]
+	expected := `{"sourceUID":"loki","targetUID":"tempo","label":"Trace","description":"Open the trace of this log line",` +
+		`"config":{"type":"query","field":"message","target":{"query":"${traceId}"},` +
+		`"transformations":[{"type":"regex","field":"message","expression":"traceID=(\\w+)","mapValue":"traceId"}]}}`
+	if req.method != "POST" || req.path != "/api/datasources/uid/loki/correlations" || req.body != expected {
+		t.Errorf("Unexpected request: %s %s %s", req.method, req.path, req.body)
+	}
+}
+
+func TestUpdateDataSourceCorrelation(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, correlationResultJSON}})
+	defer server.Close()
+
+	correlation := lokiToTempoCorrelation()
+	correlation.UID = "vJkJfYeVz"
+	correlation.Config.Transformations = []CorrelationTransformation{{Type: CorrelationTransformationLogfmt}}
+	if _, err := client.UpdateDataSourceCorrelation(correlation); err != nil {
+		t.Fatal(err)
+	}
+
+	req := server.receivedRequests[0]
+	expected := `{"label":"Trace","description":"Open the trace of this log line",` +
+		`"config":{"type":"query","field":"message","target":{"query":"${traceId}"},"transformations":[{"type":"logfmt"}]}}`
+	if req.method != "PATCH" || req.path != "/api/datasources/uid/loki/correlations/vJkJfYeVz" || req.body != expected {
+		t.Errorf("Unexpected request: %s %s %s", req.method, req.path, req.body)
+	}
+}
+
+func TestDeleteDataSourceCorrelation(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, `{"message": "Correlation deleted"}`}})
+	defer server.Close()
+
+	if err := client.DeleteDataSourceCorrelation("loki", "vJkJfYeVz"); err != nil {
+		t.Fatal(err)
+	}
+	if req := server.receivedRequests[0]; req.method != "DELETE" || req.path != "/api/datasources/uid/loki/correlations/vJkJfYeVz" {
+		t.Errorf("Unexpected request: %s %s", req.method, req.path)
+	}
+}
@@ -0,0 +1,85 @@
+package gapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// DataSourceHealth represents the result of a Grafana data source health check.
+type DataSourceHealth struct {
+	// Status is either OK, ERROR or UNKNOWN.
+	Status  string                 `json:"status"`
+	Message string                 `json:"message"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// OK returns true if the health check succeeded.
+func (h *DataSourceHealth) OK() bool {
+	return h.Status == "OK"
+}
+
+// DataSourceHealthReport represents the health check result of a single data source within a batch.
+type DataSourceHealthReport struct {
+	DataSource *DataSource
+	// Health is nil if the health check could not be run, in which case Err is set.
+	Health *DataSourceHealth
+	Err    error
+}
+
+// DataSourceHealthCheck runs the health check of the Grafana data source whose UID it's passed.
+// A failing data source is not an error, its health status is ERROR instead.
+func (c *Client) DataSourceHealthCheck(uid string) (*DataSourceHealth, error) {
+	path := fmt.Sprintf("/api/datasources/uid/%s/health", uid)
+	result := &DataSourceHealth{}
+	err := c.request("GET", path, nil, nil, result)
+	if err == nil {
+		return result, nil
+	}
+
+	// Failing health checks are reported with a 400 status code and the health check result as body.
+	var reqErr *requestError
+	if errors.As(err, &reqErr) && reqErr.StatusCode == 400 {
+		if jsonErr := json.Unmarshal(reqErr.Body, result); jsonErr == nil && result.Status != "" {
+			return result, nil
+		}
+	}
+
+	return nil, err
+}
+
+// DataSourcesHealthCheck runs the health check of every Grafana data source, with at most concurrency checks
+// running at the same time. The reports are returned in the same order as DataSources.
+func (c *Client) DataSourcesHealthCheck(concurrency int) ([]DataSourceHealthReport, error) {
+	if concurrency < 1 {
+		return nil, fmt.Errorf("concurrency must be at least 1, got %d", concurrency)
+	}
+
+	dataSources, err := c.DataSources()
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]DataSourceHealthReport, len(dataSources))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, ds := range dataSources {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, ds *DataSource) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			health, err := c.DataSourceHealthCheck(ds.UID)
+			reports[i] = DataSourceHealthReport{
+				DataSource: ds,
+				Health:     health,
+				Err:        err,
+			}
+		}(i, ds)
+	}
+	wg.Wait()
+
+	return reports, nil
+}
@@ -0,0 +1,100 @@
+package gapi
+
+import (
+	"testing"
+
+	"github.com/gobs/pretty"
+)
+
+const (
+	healthyDataSourceJSON = `
+{
+  "status": "OK",
+  "message": "Successfully queried the Prometheus API.",
+  "details": {"verboseMessage": ""}
+}
+`
+	unhealthyDataSourceJSON = `
+{
+  "status": "ERROR",
+  "message": "Post \"http://localhost:9090/api/v1/query\": dial tcp: connection refused"
+}
+`
+	healthCheckDataSourcesJSON = `
+[
+  {"id": 1, "uid": "prom", "name": "prometheus", "type": "prometheus"},
+  {"id": 2, "uid": "loki", "name": "loki", "type": "loki"},
+  {"id": 3, "uid": "gone", "name": "gone", "type": "loki"}
+]
+`
+)
+
+func TestDataSourceHealthCheck(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, healthyDataSourceJSON}})
+	defer server.Close()
+
+	health, err := client.DataSourceHealthCheck("prom")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(pretty.PrettyFormat(health))
+
+	if !health.OK() || health.Message != "Successfully queried the Prometheus API." {
+		t.Error("Not correctly parsing returned health check.")
+	}
+	if path := server.receivedRequests[0].path; path != "/api/datasources/uid/prom/health" {
+		t.Errorf("Unexpected request path: %s", path)
+	}
+}
+
+func TestDataSourceHealthCheckFailing(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{400, unhealthyDataSourceJSON}, {404, `{"message": "data source not found"}`}})
+	defer server.Close()
+
+	health, err := client.DataSourceHealthCheck("prom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if health.OK() || health.Status != "ERROR" {
+		t.Error("Expected a failing health check.")
+	}
+
+	if _, err := client.DataSourceHealthCheck("missing"); err == nil {
+		t.Error("Expected an error for a missing data source.")
+	}
+}
+
+func TestDataSourcesHealthCheck(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{
+		{200, healthCheckDataSourcesJSON},
+		{200, healthyDataSourceJSON},
+		{400, unhealthyDataSourceJSON},
+		{404, `{"message": "data source not found"}`},
+	})
+	defer server.Close()
+
+	reports, err := client.DataSourcesHealthCheck(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(pretty.PrettyFormat(reports))
+
+	if len(reports) != 3 {
+		t.Fatalf("Expected 3 reports, got %d", len(reports))
+	}
+	if reports[0].DataSource.UID != "prom" || !reports[0].Health.OK() {
+		t.Error("Expected the first data source to be healthy.")
+	}
+	if reports[1].Health == nil || reports[1].Health.OK() || reports[1].Err != nil {
+		t.Error("Expected the second data source to be unhealthy.")
+	}
+	if reports[2].Health != nil || reports[2].Err == nil {
+		t.Error("Expected the third health check to fail.")
+	}
+
+	if _, err := client.DataSourcesHealthCheck(0); err == nil {
+		t.Error("Expected an error for an invalid concurrency.")
+	}
+}
@@ -3,6 +3,8 @@ package gapi
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -122,16 +124,7 @@ type JSONData struct {
 
 // Marshal JSONData
 func (d JSONData) Map() (map[string]interface{}, error) {
-	b, err := json.Marshal(d)
-	if err != nil {
-		return nil, err
-	}
-	fields := make(map[string]interface{})
-	if err = json.Unmarshal(b, &fields); err != nil {
-		return nil, err
-	}
-
-	return fields, nil
+	return structToMap(d)
 }
 
 // SecureJSONData is a representation of the datasource `secureJsonData` property
@@ -165,7 +158,12 @@ type SecureJSONData struct {
 }
 
 func (d SecureJSONData) Map() (map[string]interface{}, error) {
-	b, err := json.Marshal(d)
+	return structToMap(d)
+}
+
+// structToMap converts a struct into a map, following its JSON representation.
+func structToMap(v interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(v)
 	if err != nil {
 		return nil, err
 	}
@@ -185,16 +183,42 @@ func cloneMap(m map[string]interface{}) map[string]interface{} {
 	return clone
 }
 
+// JSONDataWithHeaders adds custom HTTP headers to the data source JSON data and secure JSON data.
+// Headers already present in jsonData keep their index, so that rotating the value of a single header only requires
+// passing the current JSON data and that header: the values of the other headers are kept by Grafana as long as
+// they are not part of the secure JSON data sent on update.
 func JSONDataWithHeaders(jsonData, secureJSONData map[string]interface{}, headers map[string]string) (map[string]interface{}, map[string]interface{}) {
 	// Clone the maps so we don't modify the original
 	jsonData = cloneMap(jsonData)
 	secureJSONData = cloneMap(secureJSONData)
 
-	idx := 1
-	for name, value := range headers {
+	indexes := make(map[string]int)
+	for dataName, dataValue := range jsonData {
+		if !strings.HasPrefix(dataName, "httpHeaderName") {
+			continue
+		}
+		idx, err := strconv.Atoi(strings.TrimPrefix(dataName, "httpHeaderName"))
+		if name, ok := dataValue.(string); ok && err == nil {
+			indexes[name] = idx
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		idx, ok := indexes[name]
+		if !ok {
+			idx = 1
+			for jsonData[fmt.Sprintf("httpHeaderName%d", idx)] != nil {
+				idx++
+			}
+		}
 		jsonData[fmt.Sprintf("httpHeaderName%d", idx)] = name
-		secureJSONData[fmt.Sprintf("httpHeaderValue%d", idx)] = value
-		idx += 1
+		secureJSONData[fmt.Sprintf("httpHeaderValue%d", idx)] = headers[name]
 	}
 
 	return jsonData, secureJSONData
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"net/http"
 )
 
 type DatasourcePermissionType int
@@ -97,3 +98,139 @@ func (c *Client) RemoveDatasourcePermission(id, permissionID int64) error {
 
 	return nil
 }
+
+// Datasource permission levels used by the access control API.
+const (
+	DatasourcePermissionLevelQuery = "Query"
+	DatasourcePermissionLevelEdit  = "Edit"
+	DatasourcePermissionLevelAdmin = "Admin"
+)
+
+// DatasourceResourcePermission represents a datasource permission returned by the access control API.
+type DatasourceResourcePermission struct {
+	ID               int64    `json:"id"`
+	RoleName         string   `json:"roleName,omitempty"`
+	UserID           int64    `json:"userId,omitempty"`
+	UserLogin        string   `json:"userLogin,omitempty"`
+	TeamID           int64    `json:"teamId,omitempty"`
+	Team             string   `json:"team,omitempty"`
+	BuiltInRole      string   `json:"builtInRole,omitempty"`
+	IsManaged        bool     `json:"isManaged"`
+	IsInherited      bool     `json:"isInherited"`
+	IsServiceAccount bool     `json:"isServiceAccount"`
+	Actions          []string `json:"actions,omitempty"`
+	// Permission is one of DatasourcePermissionLevelQuery, DatasourcePermissionLevelEdit or DatasourcePermissionLevelAdmin.
+	Permission string `json:"permission"`
+}
+
+// DatasourcePermissionsByUID fetches and returns the permissions for the datasource whose UID it's passed.
+// Grafana versions without the access control datasource permissions API fall back to the legacy one.
+func (c *Client) DatasourcePermissionsByUID(uid string) ([]*DatasourceResourcePermission, error) {
+	path := fmt.Sprintf("/api/access-control/datasources/%s", uid)
+	permissions := make([]*DatasourceResourcePermission, 0)
+	err := c.request("GET", path, nil, nil, &permissions)
+	if err == nil {
+		return permissions, nil
+	}
+	if errorStatusCode(err) != http.StatusNotFound {
+		return nil, fmt.Errorf("error getting permissions at %s: %w", path, err)
+	}
+
+	ds, err := c.DataSourceByUID(uid)
+	if err != nil {
+		return nil, err
+	}
+	legacy, err := c.DatasourcePermissions(ds.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range legacy.Permissions {
+		permission := DatasourcePermissionLevelQuery
+		if p.Permission == DatasourcePermissionEdit {
+			permission = DatasourcePermissionLevelEdit
+		}
+		permissions = append(permissions, &DatasourceResourcePermission{
+			ID:          p.ID,
+			UserID:      p.UserID,
+			TeamID:      p.TeamID,
+			BuiltInRole: p.BuiltInRole,
+			IsManaged:   true,
+			Permission:  permission,
+		})
+	}
+
+	return permissions, nil
+}
+
+// SetDatasourceUserPermission sets the permission of a user on the datasource whose UID it's passed.
+// An empty permission removes the existing one.
+func (c *Client) SetDatasourceUserPermission(uid string, userID int64, permission string) error {
+	path := fmt.Sprintf("/api/access-control/datasources/%s/users/%d", uid, userID)
+	return c.setDatasourcePermission(uid, path, &DatasourcePermissionAddPayload{UserID: userID}, permission)
+}
+
+// SetDatasourceTeamPermission sets the permission of a team on the datasource whose UID it's passed.
+// An empty permission removes the existing one.
+func (c *Client) SetDatasourceTeamPermission(uid string, teamID int64, permission string) error {
+	path := fmt.Sprintf("/api/access-control/datasources/%s/teams/%d", uid, teamID)
+	return c.setDatasourcePermission(uid, path, &DatasourcePermissionAddPayload{TeamID: teamID}, permission)
+}
+
+// SetDatasourceBuiltInRolePermission sets the permission of a built-in role, e.g. Viewer, on the datasource
+// whose UID it's passed. An empty permission removes the existing one.
+func (c *Client) SetDatasourceBuiltInRolePermission(uid string, builtInRole string, permission string) error {
+	path := fmt.Sprintf("/api/access-control/datasources/%s/builtInRoles/%s", uid, builtInRole)
+	return c.setDatasourcePermission(uid, path, &DatasourcePermissionAddPayload{BuiltInRole: builtInRole}, permission)
+}
+
+func (c *Client) setDatasourcePermission(uid, path string, subject *DatasourcePermissionAddPayload, permission string) error {
+	data, err := json.Marshal(map[string]string{"permission": permission})
+	if err != nil {
+		return fmt.Errorf("marshal err: %w", err)
+	}
+
+	err = c.request("POST", path, nil, bytes.NewBuffer(data), nil)
+	if err == nil {
+		return nil
+	}
+	if errorStatusCode(err) != http.StatusNotFound {
+		return fmt.Errorf("error setting permissions at %s: %w", path, err)
+	}
+
+	return c.setLegacyDatasourcePermission(uid, subject, permission)
+}
+
+// setLegacyDatasourcePermission replaces the permission of a subject using the legacy datasource permissions API,
+// which can only add and remove permissions.
+func (c *Client) setLegacyDatasourcePermission(uid string, subject *DatasourcePermissionAddPayload, permission string) error {
+	switch permission {
+	case "":
+	case DatasourcePermissionLevelQuery:
+		subject.Permission = DatasourcePermissionQuery
+	case DatasourcePermissionLevelEdit:
+		subject.Permission = DatasourcePermissionEdit
+	default:
+		return fmt.Errorf("datasource permission %q is not supported by this Grafana version", permission)
+	}
+
+	ds, err := c.DataSourceByUID(uid)
+	if err != nil {
+		return err
+	}
+	existing, err := c.DatasourcePermissions(ds.ID)
+	if err != nil {
+		return err
+	}
+	for _, p := range existing.Permissions {
+		if p.UserID == subject.UserID && p.TeamID == subject.TeamID && p.BuiltInRole == subject.BuiltInRole {
+			if err := c.RemoveDatasourcePermission(ds.ID, p.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	if permission == "" {
+		return nil
+	}
+	return c.AddDatasourcePermission(ds.ID, subject)
+}
@@ -112,3 +112,127 @@ func TestAddDatasourcePermissions(t *testing.T) {
 		}
 	}
 }
+
+const (
+	getDatasourceResourcePermissionsJSON = `
+[
+  {
+    "id": 1,
+    "roleName": "basic:admin",
+    "isManaged": false,
+    "isInherited": false,
+    "isServiceAccount": false,
+    "builtInRole": "Admin",
+    "actions": ["datasources:query", "datasources:read", "datasources:write"],
+    "permission": "Admin"
+  },
+  {
+    "id": 4,
+    "roleName": "managed:teams:1:permissions",
+    "isManaged": true,
+    "isInherited": false,
+    "isServiceAccount": false,
+    "teamId": 1,
+    "team": "A Team",
+    "actions": ["datasources:query"],
+    "permission": "Query"
+  }
+]
+`
+	getLegacyDatasourcePermissionsJSON = `
+{
+  "datasourceId": 1,
+  "enabled": true,
+  "permissions": [
+    {"id": 7, "datasourceId": 1, "userId": 1, "permission": 1, "permissionName": "Query"},
+    {"id": 8, "datasourceId": 1, "teamId": 1, "permission": 2, "permissionName": "Edit"}
+  ]
+}
+`
+)
+
+func TestDatasourcePermissionsByUID(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, getDatasourceResourcePermissionsJSON}})
+	defer server.Close()
+
+	permissions, err := client.DatasourcePermissionsByUID("ds")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(pretty.PrettyFormat(permissions))
+
+	if len(permissions) != 2 || permissions[1].TeamID != 1 || permissions[1].Permission != DatasourcePermissionLevelQuery || permissions[0].IsManaged {
+		t.Error("Not correctly parsing returned datasource permissions.")
+	}
+	if path := server.receivedRequests[0].path; path != "/api/access-control/datasources/ds" {
+		t.Errorf("Unexpected request path: %s", path)
+	}
+}
+
+func TestDatasourcePermissionsByUIDFallback(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{
+		{404, `{"message": "Not found"}`},
+		{200, `{"id": 1, "uid": "ds"}`},
+		{200, getLegacyDatasourcePermissionsJSON},
+	})
+	defer server.Close()
+
+	permissions, err := client.DatasourcePermissionsByUID("ds")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(permissions) != 2 || permissions[0].UserID != 1 || permissions[1].Permission != DatasourcePermissionLevelEdit {
+		t.Errorf("Not correctly converting legacy datasource permissions: %s", pretty.PrettyFormat(permissions))
+	}
+	if path := server.receivedRequests[2].path; path != "/api/datasources/1/permissions" {
+		t.Errorf("Unexpected legacy request path: %s", path)
+	}
+}
+
+func TestSetDatasourceTeamPermission(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, `{"message": "Permission updated"}`}})
+	defer server.Close()
+
+	if err := client.SetDatasourceTeamPermission("ds", 1, DatasourcePermissionLevelAdmin); err != nil {
+		t.Fatal(err)
+	}
+
+	req := server.receivedRequests[0]
+	if req.method != "POST" || req.path != "/api/access-control/datasources/ds/teams/1" || req.body != `{"permission":"Admin"}` {
+		t.Errorf("Unexpected request: %s %s %s", req.method, req.path, req.body)
+	}
+}
+
+func TestSetDatasourceTeamPermissionFallback(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{
+		{404, `{"message": "Not found"}`},
+		{200, `{"id": 1, "uid": "ds"}`},
+		{200, getLegacyDatasourcePermissionsJSON},
+		{200, `{"message": "Datasource permission removed"}`},
+		{200, `{"message": "Datasource permission added"}`},
+	})
+	defer server.Close()
+
+	if err := client.SetDatasourceTeamPermission("ds", 1, DatasourcePermissionLevelQuery); err != nil {
+		t.Fatal(err)
+	}
+
+	remove := server.receivedRequests[3]
+	if remove.method != "DELETE" || remove.path != "/api/datasources/1/permissions/8" {
+		t.Errorf("Unexpected remove request: %s %s", remove.method, remove.path)
+	}
+	add := server.receivedRequests[4]
+	if add.method != "POST" || add.body != `{"userId":0,"teamId":1,"builtinRole":"","permission":1}` {
+		t.Errorf("Unexpected add request: %s %s", add.method, add.body)
+	}
+}
+
+func TestSetDatasourceBuiltInRolePermissionFallbackUnsupported(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{404, `{"message": "Not found"}`}})
+	defer server.Close()
+
+	if err := client.SetDatasourceBuiltInRolePermission("ds", "Viewer", DatasourcePermissionLevelAdmin); err == nil {
+		t.Error("Expected an error for a permission unsupported by the legacy API.")
+	}
+}
@@ -0,0 +1,215 @@
+package gapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// DataSourceProxy sends a request to the data source whose UID it's passed through the Grafana data source proxy,
+// e.g. with path "api/v1/labels" for a Prometheus data source. The body, if any, is sent as JSON and the response
+// is decoded as JSON into responseStruct; use a *[]byte to get the raw response, e.g. when it isn't JSON.
+func (c *Client) DataSourceProxy(uid, method, path string, query url.Values, body interface{}, responseStruct interface{}) error {
+	return c.dataSourceCall(fmt.Sprintf("/api/datasources/proxy/uid/%s/%s", uid, path), method, query, body, responseStruct)
+}
+
+// DataSourceResource calls a resource of the plugin backing the data source whose UID it's passed,
+// e.g. with path "labels" for a Loki data source. The body, if any, is sent as JSON and the response
+// is decoded as JSON into responseStruct; use a *[]byte to get the raw response, e.g. when it isn't JSON.
+func (c *Client) DataSourceResource(uid, method, path string, query url.Values, body interface{}, responseStruct interface{}) error {
+	return c.dataSourceCall(fmt.Sprintf("/api/datasources/uid/%s/resources/%s", uid, path), method, query, body, responseStruct)
+}
+
+func (c *Client) dataSourceCall(requestPath, method string, query url.Values, body interface{}, responseStruct interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewBuffer(data)
+	}
+
+	return c.request(method, requestPath, query, reader, responseStruct)
+}
+
+// PrometheusMetricMetadata represents the metadata of a Prometheus metric.
+type PrometheusMetricMetadata struct {
+	Type string `json:"type"`
+	Help string `json:"help"`
+	Unit string `json:"unit"`
+}
+
+// prometheusResponse represents the response envelope of the Prometheus and Loki HTTP APIs.
+type prometheusResponse struct {
+	Status    string          `json:"status"`
+	Data      json.RawMessage `json:"data"`
+	ErrorType string          `json:"errorType,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+func (r *prometheusResponse) decode(data interface{}) error {
+	if r.Status != "success" {
+		return fmt.Errorf("%s: %s", r.ErrorType, r.Error)
+	}
+	return json.Unmarshal(r.Data, data)
+}
+
+// prometheusSeriesQuery returns the query parameters selecting series within the time range. Zero times are omitted.
+func prometheusSeriesQuery(matchers []string, start, end time.Time, formatTime func(time.Time) string) url.Values {
+	query := url.Values{}
+	for _, m := range matchers {
+		query.Add("match[]", m)
+	}
+	if !start.IsZero() {
+		query.Set("start", formatTime(start))
+	}
+	if !end.IsZero() {
+		query.Set("end", formatTime(end))
+	}
+	return query
+}
+
+func prometheusTime(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10)
+}
+
+func lokiTime(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// PrometheusLabelNames returns the label names of the series matching the matchers within the time range,
+// using the Prometheus data source whose UID it's passed. Zero times and no matchers select everything.
+func (c *Client) PrometheusLabelNames(uid string, start, end time.Time, matchers ...string) ([]string, error) {
+	resp := prometheusResponse{}
+	query := prometheusSeriesQuery(matchers, start, end, prometheusTime)
+	if err := c.DataSourceProxy(uid, "GET", "api/v1/labels", query, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	labels := make([]string, 0)
+	if err := resp.decode(&labels); err != nil {
+		return nil, err
+	}
+
+	return labels, nil
+}
+
+// PrometheusLabelValues returns the values of the label of the series matching the matchers within the time range,
+// using the Prometheus data source whose UID it's passed.
+func (c *Client) PrometheusLabelValues(uid, label string, start, end time.Time, matchers ...string) ([]string, error) {
+	resp := prometheusResponse{}
+	query := prometheusSeriesQuery(matchers, start, end, prometheusTime)
+	path := fmt.Sprintf("api/v1/label/%s/values", label)
+	if err := c.DataSourceProxy(uid, "GET", path, query, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	values := make([]string, 0)
+	if err := resp.decode(&values); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// PrometheusSeries returns the label sets of the series matching the matchers within the time range,
+// using the Prometheus data source whose UID it's passed. At least one matcher is required.
+func (c *Client) PrometheusSeries(uid string, start, end time.Time, matchers ...string) ([]map[string]string, error) {
+	if len(matchers) == 0 {
+		return nil, fmt.Errorf("at least one series matcher is required")
+	}
+
+	resp := prometheusResponse{}
+	query := prometheusSeriesQuery(matchers, start, end, prometheusTime)
+	if err := c.DataSourceProxy(uid, "GET", "api/v1/series", query, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	series := make([]map[string]string, 0)
+	if err := resp.decode(&series); err != nil {
+		return nil, err
+	}
+
+	return series, nil
+}
+
+// PrometheusMetricMetadata returns the metadata of the metric whose name it's passed, or of every metric if it's empty,
+// using the Prometheus data source whose UID it's passed.
+func (c *Client) PrometheusMetricMetadata(uid, metric string) (map[string][]PrometheusMetricMetadata, error) {
+	resp := prometheusResponse{}
+	query := url.Values{}
+	if metric != "" {
+		query.Set("metric", metric)
+	}
+	if err := c.DataSourceProxy(uid, "GET", "api/v1/metadata", query, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	metadata := make(map[string][]PrometheusMetricMetadata)
+	if err := resp.decode(&metadata); err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
+
+// LokiLabelNames returns the label names of the log streams within the time range,
+// using the Loki data source whose UID it's passed. Zero times use the Loki defaults.
+func (c *Client) LokiLabelNames(uid string, start, end time.Time) ([]string, error) {
+	resp := prometheusResponse{}
+	query := prometheusSeriesQuery(nil, start, end, lokiTime)
+	if err := c.DataSourceResource(uid, "GET", "labels", query, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	labels := make([]string, 0)
+	if err := resp.decode(&labels); err != nil {
+		return nil, err
+	}
+
+	return labels, nil
+}
+
+// LokiLabelValues returns the values of the label of the log streams within the time range,
+// using the Loki data source whose UID it's passed.
+func (c *Client) LokiLabelValues(uid, label string, start, end time.Time) ([]string, error) {
+	resp := prometheusResponse{}
+	query := prometheusSeriesQuery(nil, start, end, lokiTime)
+	path := fmt.Sprintf("label/%s/values", label)
+	if err := c.DataSourceResource(uid, "GET", path, query, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	values := make([]string, 0)
+	if err := resp.decode(&values); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// LokiSeries returns the label sets of the log streams matching the matchers within the time range,
+// using the Loki data source whose UID it's passed. At least one matcher is required.
+func (c *Client) LokiSeries(uid string, start, end time.Time, matchers ...string) ([]map[string]string, error) {
+	if len(matchers) == 0 {
+		return nil, fmt.Errorf("at least one series matcher is required")
+	}
+
+	resp := prometheusResponse{}
+	query := prometheusSeriesQuery(matchers, start, end, lokiTime)
+	if err := c.DataSourceResource(uid, "GET", "series", query, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	series := make([]map[string]string, 0)
+	if err := resp.decode(&series); err != nil {
+		return nil, err
+	}
+
+	return series, nil
+}
@@ -0,0 +1,131 @@
+package gapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gobs/pretty"
+)
+
+const (
+	prometheusLabelsJSON   = `{"status": "success", "data": ["__name__", "instance", "job"]}`
+	prometheusSeriesJSON   = `{"status": "success", "data": [{"__name__": "up", "job": "grafana", "instance": "localhost:3000"}]}`
+	prometheusErrorJSON    = `{"status": "error", "errorType": "bad_data", "error": "invalid parameter \"match[]\""}`
+	prometheusMetadataJSON = `
+{
+  "status": "success",
+  "data": {
+    "up": [{"type": "gauge", "help": "Whether the target is up.", "unit": ""}]
+  }
+}
+`
+	lokiLabelValuesJSON = `{"status": "success", "data": ["api", "web"]}`
+)
+
+func TestDataSourceProxy(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, "# HELP up\nup 1\n"}})
+	defer server.Close()
+
+	var raw []byte
+	err := client.DataSourceProxy("prom", "POST", "api/v1/custom", nil, map[string]string{"key": "value"}, &raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := server.receivedRequests[0]
+	if req.method != "POST" || req.path != "/api/datasources/proxy/uid/prom/api/v1/custom" || req.body != `{"key":"value"}` {
+		t.Errorf("Unexpected request: %s %s %s", req.method, req.path, req.body)
+	}
+	if string(raw) != "# HELP up\nup 1\n" {
+		t.Errorf("Unexpected response: %s", raw)
+	}
+}
+
+func TestPrometheusLabelNames(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, prometheusLabelsJSON}})
+	defer server.Close()
+
+	start := time.Unix(1660000000, 0)
+	labels, err := client.PrometheusLabelNames("prom", start, start.Add(time.Hour), `up{job="grafana"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(pretty.PrettyFormat(labels))
+
+	if len(labels) != 3 || labels[2] != "job" {
+		t.Error("Not correctly parsing returned label names.")
+	}
+	query := server.receivedRequests[0].query
+	if query.Get("start") != "1660000000" || query.Get("end") != "1660003600" || query.Get("match[]") != `up{job="grafana"}` {
+		t.Errorf("Unexpected query: %v", query)
+	}
+}
+
+func TestPrometheusLabelValues(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, lokiLabelValuesJSON}})
+	defer server.Close()
+
+	values, err := client.PrometheusLabelValues("prom", "job", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != 2 {
+		t.Error("Not correctly parsing returned label values.")
+	}
+	req := server.receivedRequests[0]
+	if req.path != "/api/datasources/proxy/uid/prom/api/v1/label/job/values" || len(req.query) != 0 {
+		t.Errorf("Unexpected request: %s %v", req.path, req.query)
+	}
+}
+
+func TestPrometheusSeries(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, prometheusSeriesJSON}, {200, prometheusErrorJSON}})
+	defer server.Close()
+
+	series, err := client.PrometheusSeries("prom", time.Time{}, time.Time{}, "up")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != 1 || series[0]["job"] != "grafana" {
+		t.Error("Not correctly parsing returned series.")
+	}
+
+	if _, err := client.PrometheusSeries("prom", time.Time{}, time.Time{}, "{"); err == nil {
+		t.Error("Expected an error for a failed Prometheus request.")
+	}
+	if _, err := client.PrometheusSeries("prom", time.Time{}, time.Time{}); err == nil {
+		t.Error("Expected an error without matchers.")
+	}
+}
+
+func TestPrometheusMetricMetadata(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, prometheusMetadataJSON}})
+	defer server.Close()
+
+	metadata, err := client.PrometheusMetricMetadata("prom", "up")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(metadata["up"]) != 1 || metadata["up"][0].Type != "gauge" {
+		t.Error("Not correctly parsing returned metadata.")
+	}
+}
+
+func TestLokiLabelValues(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, lokiLabelValuesJSON}})
+	defer server.Close()
+
+	start := time.Unix(1660000000, 0)
+	values, err := client.LokiLabelValues("loki", "app", start, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != 2 || values[0] != "api" {
+		t.Error("Not correctly parsing returned label values.")
+	}
+	req := server.receivedRequests[0]
+	if req.path != "/api/datasources/uid/loki/resources/label/app/values" || req.query.Get("start") != "1660000000000000000" {
+		t.Errorf("Unexpected request: %s %v", req.path, req.query)
+	}
+}
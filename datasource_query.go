@@ -0,0 +1,317 @@
+package gapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// DataSourceQuery represents a query run through a Grafana data source.
+type DataSourceQuery struct {
+	RefID          string
+	DatasourceUID  string
+	DatasourceType string
+	MaxDataPoints  int64
+	Interval       time.Duration
+	// Model contains the data source specific query properties, e.g. "expr" for Prometheus.
+	Model map[string]interface{}
+}
+
+// MarshalJSON implements the json.Marshaler interface for DataSourceQuery.
+func (q DataSourceQuery) MarshalJSON() ([]byte, error) {
+	query := cloneMap(q.Model)
+	query["refId"] = q.RefID
+	datasource := map[string]string{"uid": q.DatasourceUID}
+	if q.DatasourceType != "" {
+		datasource["type"] = q.DatasourceType
+	}
+	query["datasource"] = datasource
+	if q.MaxDataPoints != 0 {
+		query["maxDataPoints"] = q.MaxDataPoints
+	}
+	if q.Interval != 0 {
+		query["intervalMs"] = q.Interval.Milliseconds()
+	}
+	return json.Marshal(query)
+}
+
+// QueryTimeRange represents the time range of data source queries.
+// From and To accept relative times such as "now-1h" as well as epoch milliseconds.
+type QueryTimeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// AbsoluteTimeRange returns the query time range between two points in time.
+func AbsoluteTimeRange(from, to time.Time) QueryTimeRange {
+	return QueryTimeRange{
+		From: strconv.FormatInt(from.UnixNano()/int64(time.Millisecond), 10),
+		To:   strconv.FormatInt(to.UnixNano()/int64(time.Millisecond), 10),
+	}
+}
+
+// QueryDataResponse represents the Grafana API response to data source queries, keyed by query ref ID.
+type QueryDataResponse struct {
+	Results map[string]QueryDataResult `json:"results"`
+}
+
+// QueryDataResult represents the result of a single data source query.
+type QueryDataResult struct {
+	Status int         `json:"status,omitempty"`
+	Error  string      `json:"error,omitempty"`
+	Frames []DataFrame `json:"frames"`
+}
+
+// Data frame field types.
+const (
+	DataFrameFieldTypeTime    = "time"
+	DataFrameFieldTypeNumber  = "number"
+	DataFrameFieldTypeString  = "string"
+	DataFrameFieldTypeBoolean = "boolean"
+)
+
+// DataFrame represents a Grafana data frame, a table of fields sharing the same length.
+type DataFrame struct {
+	Name   string
+	RefID  string
+	Meta   map[string]interface{}
+	Fields []DataFrameField
+}
+
+// DataFrameField represents a column of a data frame.
+type DataFrameField struct {
+	Name   string
+	Type   string
+	Labels map[string]string
+	Config map[string]interface{}
+	// Values holds time.Time values for time fields, float64 for number fields, string for string fields
+	// and bool for boolean fields. Null values are nil.
+	Values []interface{}
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for DataFrame.
+func (f *DataFrame) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Schema struct {
+			Name   string                 `json:"name"`
+			RefID  string                 `json:"refId"`
+			Meta   map[string]interface{} `json:"meta"`
+			Fields []struct {
+				Name   string                 `json:"name"`
+				Type   string                 `json:"type"`
+				Labels map[string]string      `json:"labels"`
+				Config map[string]interface{} `json:"config"`
+			} `json:"fields"`
+		} `json:"schema"`
+		Data struct {
+			Values   [][]interface{} `json:"values"`
+			Entities []*struct {
+				NaN    []int `json:"NaN"`
+				Inf    []int `json:"Inf"`
+				NegInf []int `json:"NegInf"`
+			} `json:"entities"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	f.Name = raw.Schema.Name
+	f.RefID = raw.Schema.RefID
+	f.Meta = raw.Schema.Meta
+	f.Fields = make([]DataFrameField, len(raw.Schema.Fields))
+	for i, schema := range raw.Schema.Fields {
+		field := DataFrameField{
+			Name:   schema.Name,
+			Type:   schema.Type,
+			Labels: schema.Labels,
+			Config: schema.Config,
+		}
+		if i < len(raw.Data.Values) {
+			values, err := decodeDataFrameValues(schema.Type, raw.Data.Values[i])
+			if err != nil {
+				return fmt.Errorf("field %s: %w", schema.Name, err)
+			}
+			field.Values = values
+		}
+		// Special float values can't be represented in JSON, they are sent as entities instead.
+		if i < len(raw.Data.Entities) && raw.Data.Entities[i] != nil {
+			entities := raw.Data.Entities[i]
+			setDataFrameEntities(field.Values, entities.NaN, math.NaN())
+			setDataFrameEntities(field.Values, entities.Inf, math.Inf(1))
+			setDataFrameEntities(field.Values, entities.NegInf, math.Inf(-1))
+		}
+		f.Fields[i] = field
+	}
+
+	return nil
+}
+
+func decodeDataFrameValues(fieldType string, raw []interface{}) ([]interface{}, error) {
+	values := make([]interface{}, len(raw))
+	for i, v := range raw {
+		if v == nil {
+			continue
+		}
+		switch fieldType {
+		case DataFrameFieldTypeTime:
+			ms, ok := v.(float64)
+			if !ok {
+				return nil, fmt.Errorf("invalid time value %v", v)
+			}
+			values[i] = time.Unix(0, int64(ms)*int64(time.Millisecond)).UTC()
+		case DataFrameFieldTypeNumber:
+			n, ok := v.(float64)
+			if !ok {
+				return nil, fmt.Errorf("invalid number value %v", v)
+			}
+			values[i] = n
+		default:
+			values[i] = v
+		}
+	}
+	return values, nil
+}
+
+func setDataFrameEntities(values []interface{}, indexes []int, value float64) {
+	for _, i := range indexes {
+		if i >= 0 && i < len(values) {
+			values[i] = value
+		}
+	}
+}
+
+// TimeSeries represents a single time series extracted from a data frame.
+type TimeSeries struct {
+	Name   string
+	Labels map[string]string
+	Points []TimeSeriesPoint
+}
+
+// TimeSeriesPoint represents a single value of a time series.
+type TimeSeriesPoint struct {
+	Time  time.Time
+	Value float64
+}
+
+// TimeSeries converts the data frame into time series, one per number field, using its first time field
+// as timestamps. Points with a null time or value are skipped.
+func (f DataFrame) TimeSeries() ([]TimeSeries, error) {
+	var timeField *DataFrameField
+	for i := range f.Fields {
+		if f.Fields[i].Type == DataFrameFieldTypeTime {
+			timeField = &f.Fields[i]
+			break
+		}
+	}
+	if timeField == nil {
+		return nil, fmt.Errorf("data frame %s has no time field", f.Name)
+	}
+
+	valueFields := 0
+	for _, field := range f.Fields {
+		if field.Type == DataFrameFieldTypeNumber {
+			valueFields++
+		}
+	}
+
+	series := make([]TimeSeries, 0)
+	for _, field := range f.Fields {
+		if field.Type != DataFrameFieldTypeNumber {
+			continue
+		}
+		s := TimeSeries{
+			Name:   field.displayName(f.Name, valueFields > 1),
+			Labels: field.Labels,
+			Points: make([]TimeSeriesPoint, 0, len(field.Values)),
+		}
+		for i, v := range field.Values {
+			if i >= len(timeField.Values) || v == nil || timeField.Values[i] == nil {
+				continue
+			}
+			s.Points = append(s.Points, TimeSeriesPoint{
+				Time:  timeField.Values[i].(time.Time),
+				Value: v.(float64),
+			})
+		}
+		series = append(series, s)
+	}
+
+	return series, nil
+}
+
+// displayName returns the name of the series of the field. Like Grafana, it combines the frame and field names
+// when the frame has several value fields, so that their series can be told apart.
+func (field DataFrameField) displayName(frameName string, multipleValues bool) string {
+	for _, key := range []string{"displayNameFromDS", "displayName"} {
+		if name, ok := field.Config[key].(string); ok && name != "" {
+			return name
+		}
+	}
+	switch {
+	case frameName == "":
+		return field.Name
+	case multipleValues && field.Name != "":
+		return frameName + " " + field.Name
+	default:
+		return frameName
+	}
+}
+
+// TimeSeries returns the time series of every data frame of the query whose ref ID it's passed.
+func (r *QueryDataResponse) TimeSeries(refID string) ([]TimeSeries, error) {
+	result, ok := r.Results[refID]
+	if !ok {
+		return nil, fmt.Errorf("no result for query %s", refID)
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("query %s failed: %s", refID, result.Error)
+	}
+
+	series := make([]TimeSeries, 0)
+	for _, frame := range result.Frames {
+		frameSeries, err := frame.TimeSeries()
+		if err != nil {
+			return nil, err
+		}
+		series = append(series, frameSeries...)
+	}
+
+	return series, nil
+}
+
+// QueryDataSources runs the given queries through their Grafana data sources over the time range.
+// Queries failing on the data source side don't cause an error, it's reported in their result instead.
+func (c *Client) QueryDataSources(timeRange QueryTimeRange, queries ...DataSourceQuery) (*QueryDataResponse, error) {
+	payload := struct {
+		QueryTimeRange
+		Queries []DataSourceQuery `json:"queries"`
+	}{
+		QueryTimeRange: timeRange,
+		Queries:        queries,
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &QueryDataResponse{}
+	err = c.request("POST", "/api/ds/query", nil, bytes.NewBuffer(data), result)
+	if err == nil {
+		return result, nil
+	}
+
+	// Query errors are reported with an error status code along with the results of every query.
+	var reqErr *requestError
+	if errors.As(err, &reqErr) && reqErr.StatusCode < 500 {
+		if jsonErr := json.Unmarshal(reqErr.Body, result); jsonErr == nil && len(result.Results) > 0 {
+			return result, nil
+		}
+	}
+
+	return nil, err
+}
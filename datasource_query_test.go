@@ -0,0 +1,149 @@
+package gapi
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/gobs/pretty"
+)
+
+const (
+	queryDataSourcesJSON = `
+{
+  "results": {
+    "A": {
+      "status": 200,
+      "frames": [
+        {
+          "schema": {
+            "name": "up",
+            "refId": "A",
+            "meta": {"type": "timeseries-multi"},
+            "fields": [
+              {"name": "Time", "type": "time", "typeInfo": {"frame": "time.Time"}, "config": {"interval": 15000}},
+              {"name": "Value", "type": "number", "typeInfo": {"frame": "float64"}, "labels": {"job": "grafana"}, "config": {"displayNameFromDS": "grafana up"}}
+            ]
+          },
+          "data": {
+            "values": [
+              [1660000000000, 1660000015000, 1660000030000, 1660000045000],
+              [1, null, 0, null]
+            ],
+            "entities": [null, {"NaN": [3]}]
+          }
+        }
+      ]
+    },
+    "B": {
+      "status": 400,
+      "error": "bad_data: parse error"
+    }
+  }
+}
+`
+)
+
+func TestQueryDataSources(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{400, queryDataSourcesJSON}})
+	defer server.Close()
+
+	from := time.Unix(1660000000, 0)
+	resp, err := client.QueryDataSources(AbsoluteTimeRange(from, from.Add(time.Minute)),
+		DataSourceQuery{
+			RefID:         "A",
+			DatasourceUID: "prom",
+			MaxDataPoints: 100,
+			Interval:      15 * time.Second,
+			Model:         map[string]interface{}{"expr": "up"},
+		},
+		DataSourceQuery{
+			RefID:         "B",
+			DatasourceUID: "prom",
+			Model:         map[string]interface{}{"expr": "up{"},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(pretty.PrettyFormat(resp))
+
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(server.receivedRequests[0].body), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body["from"] != "1660000000000" || body["to"] != "1660000060000" {
+		t.Errorf("Unexpected time range: %v - %v", body["from"], body["to"])
+	}
+	query := body["queries"].([]interface{})[0].(map[string]interface{})
+	if query["refId"] != "A" || query["expr"] != "up" || query["intervalMs"] != float64(15000) || query["maxDataPoints"] != float64(100) {
+		t.Errorf("Unexpected query: %v", query)
+	}
+
+	frame := resp.Results["A"].Frames[0]
+	if frame.Name != "up" || len(frame.Fields) != 2 || frame.Fields[1].Labels["job"] != "grafana" {
+		t.Error("Not correctly parsing returned data frame.")
+	}
+	if ts, ok := frame.Fields[0].Values[1].(time.Time); !ok || !ts.Equal(time.Unix(1660000015, 0)) {
+		t.Errorf("Not correctly parsing time values: %v", frame.Fields[0].Values)
+	}
+	if frame.Fields[1].Values[1] != nil || !math.IsNaN(frame.Fields[1].Values[3].(float64)) {
+		t.Errorf("Not correctly parsing number values: %v", frame.Fields[1].Values)
+	}
+
+	series, err := resp.TimeSeries("A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != 1 || series[0].Name != "grafana up" || len(series[0].Points) != 3 {
+		t.Errorf("Unexpected time series: %s", pretty.PrettyFormat(series))
+	}
+	if series[0].Points[1].Value != 0 {
+		t.Errorf("Unexpected point value: %v", series[0].Points[1].Value)
+	}
+
+	if _, err := resp.TimeSeries("B"); err == nil {
+		t.Error("Expected an error for a failed query.")
+	}
+}
+
+func TestQueryDataSourcesError(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{401, `{"message": "Unauthorized"}`}})
+	defer server.Close()
+
+	_, err := client.QueryDataSources(QueryTimeRange{From: "now-1h", To: "now"}, DataSourceQuery{RefID: "A", DatasourceUID: "prom"})
+	if err == nil {
+		t.Error("Expected an error for an unauthorized request.")
+	}
+}
+
+func TestDataFrameTimeSeriesNames(t *testing.T) {
+	now := time.Unix(1660000000, 0).UTC()
+	frame := DataFrame{
+		Name: "cpu",
+		Fields: []DataFrameField{
+			{Name: "time", Type: DataFrameFieldTypeTime, Values: []interface{}{now}},
+			{Name: "user", Type: DataFrameFieldTypeNumber, Values: []interface{}{1.0}},
+			{Name: "system", Type: DataFrameFieldTypeNumber, Values: []interface{}{2.0}},
+		},
+	}
+
+	series, err := frame.TimeSeries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != 2 || series[0].Name != "cpu user" || series[1].Name != "cpu system" {
+		t.Errorf("Unexpected time series: %s", pretty.PrettyFormat(series))
+	}
+
+	frame.Fields = frame.Fields[:2]
+	series, err = frame.TimeSeries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != 1 || series[0].Name != "cpu" {
+		t.Errorf("Unexpected time series: %s", pretty.PrettyFormat(series))
+	}
+}
@@ -0,0 +1,647 @@
+package gapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// Datasource types supported by the typed datasource settings.
+const (
+	DataSourceTypePrometheus    = "prometheus"
+	DataSourceTypeLoki          = "loki"
+	DataSourceTypeTempo         = "tempo"
+	DataSourceTypeElasticsearch = "elasticsearch"
+	DataSourceTypeInfluxDB      = "influxdb"
+	DataSourceTypePostgres      = "postgres"
+	// DataSourceTypePostgresPlugin is the type of PostgreSQL datasources from Grafana 10.3.
+	DataSourceTypePostgresPlugin = "grafana-postgresql-datasource"
+	DataSourceTypeMySQL          = "mysql"
+	DataSourceTypeMSSQL          = "mssql"
+	DataSourceTypeCloudWatch     = "cloudwatch"
+	DataSourceTypeAzureMonitor   = "grafana-azure-monitor-datasource"
+)
+
+// DataSourceSettings is implemented by the typed settings of each supported datasource type.
+// They marshal into the `jsonData` and `secureJsonData` properties of a DataSource.
+type DataSourceSettings interface {
+	// DataSourceType returns the type of the datasources the settings apply to.
+	DataSourceType() string
+	// Validate returns an error if a required setting is missing or invalid.
+	// Secure settings are not validated as they can't be read back from Grafana.
+	Validate() error
+	// JSONDataMap returns the settings stored in `jsonData`.
+	JSONDataMap() (map[string]interface{}, error)
+	// SecureJSONDataMap returns the settings stored in `secureJsonData`.
+	SecureJSONDataMap() (map[string]interface{}, error)
+}
+
+// SetSettings validates the typed settings and sets the type, `jsonData` and `secureJsonData` of the datasource from them.
+// Both properties are replaced, custom HTTP headers can be added afterwards with JSONDataWithHeaders.
+func (ds *DataSource) SetSettings(s DataSourceSettings) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+
+	jsonData, err := s.JSONDataMap()
+	if err != nil {
+		return err
+	}
+	secureJSONData, err := s.SecureJSONDataMap()
+	if err != nil {
+		return err
+	}
+
+	ds.Type = s.DataSourceType()
+	ds.JSONData = jsonData
+	ds.SecureJSONData = secureJSONData
+	return nil
+}
+
+// Settings decodes the `jsonData` of the datasource into the typed settings matching its type.
+// Secure settings are left empty as Grafana never returns them.
+func (ds *DataSource) Settings() (DataSourceSettings, error) {
+	var settings DataSourceSettings
+	switch ds.Type {
+	case DataSourceTypePrometheus:
+		settings = &PrometheusSettings{}
+	case DataSourceTypeLoki:
+		settings = &LokiSettings{}
+	case DataSourceTypeTempo:
+		settings = &TempoSettings{}
+	case DataSourceTypeElasticsearch:
+		settings = &ElasticsearchSettings{}
+	case DataSourceTypeInfluxDB:
+		if version, _ := ds.JSONData["version"].(string); version == influxDBVersionFlux {
+			settings = &InfluxDBFluxSettings{}
+		} else {
+			settings = &InfluxDBInfluxQLSettings{}
+		}
+	case DataSourceTypePostgres, DataSourceTypePostgresPlugin:
+		settings = &PostgresSettings{Type: ds.Type}
+	case DataSourceTypeMySQL:
+		settings = &MySQLSettings{}
+	case DataSourceTypeMSSQL:
+		settings = &MSSQLSettings{}
+	case DataSourceTypeCloudWatch:
+		settings = &CloudWatchSettings{}
+	case DataSourceTypeAzureMonitor:
+		settings = &AzureMonitorSettings{}
+	default:
+		return nil, fmt.Errorf("no typed settings for datasource type %q", ds.Type)
+	}
+
+	data, err := json.Marshal(ds.JSONData)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, settings); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+func validateOneOf(setting, value string, allowed ...string) error {
+	if value == "" {
+		return nil
+	}
+	for _, a := range allowed {
+		if value == a {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid %s %q, expected one of %v", setting, value, allowed)
+}
+
+func defaultString(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+// TLSSettings are the TLS settings shared by HTTP based datasources.
+type TLSSettings struct {
+	TLSAuth           bool `json:"tlsAuth,omitempty"`
+	TLSAuthWithCACert bool `json:"tlsAuthWithCACert,omitempty"`
+	TLSSkipVerify     bool `json:"tlsSkipVerify,omitempty"`
+}
+
+// TLSSecureSettings are the secure TLS settings shared by HTTP based datasources.
+type TLSSecureSettings struct {
+	TLSCACert     string `json:"tlsCACert,omitempty"`
+	TLSClientCert string `json:"tlsClientCert,omitempty"`
+	TLSClientKey  string `json:"tlsClientKey,omitempty"`
+}
+
+// HTTPSecureSettings are the secure settings shared by HTTP based datasources.
+type HTTPSecureSettings struct {
+	TLSSecureSettings
+	BasicAuthPassword string `json:"basicAuthPassword,omitempty"`
+}
+
+// PrometheusExemplarTraceIDDestination links exemplars to traces, either in a datasource or at an external URL.
+type PrometheusExemplarTraceIDDestination struct {
+	Name            string `json:"name"`
+	DatasourceUID   string `json:"datasourceUid,omitempty"`
+	URL             string `json:"url,omitempty"`
+	URLDisplayLabel string `json:"urlDisplayLabel,omitempty"`
+}
+
+// PrometheusSettings are the settings of Prometheus datasources.
+type PrometheusSettings struct {
+	TLSSettings
+	HTTPMethod                  string                                 `json:"httpMethod,omitempty"`
+	TimeInterval                string                                 `json:"timeInterval,omitempty"`
+	QueryTimeout                string                                 `json:"queryTimeout,omitempty"`
+	CustomQueryParameters       string                                 `json:"customQueryParameters,omitempty"`
+	PrometheusType              string                                 `json:"prometheusType,omitempty"`
+	PrometheusVersion           string                                 `json:"prometheusVersion,omitempty"`
+	ManageAlerts                bool                                   `json:"manageAlerts,omitempty"`
+	AlertmanagerUID             string                                 `json:"alertmanagerUid,omitempty"`
+	ExemplarTraceIDDestinations []PrometheusExemplarTraceIDDestination `json:"exemplarTraceIdDestinations,omitempty"`
+
+	Secure HTTPSecureSettings `json:"-"`
+}
+
+func (s *PrometheusSettings) DataSourceType() string {
+	return DataSourceTypePrometheus
+}
+
+func (s *PrometheusSettings) Validate() error {
+	if err := validateOneOf("httpMethod", s.HTTPMethod, "GET", "POST"); err != nil {
+		return err
+	}
+	for _, d := range s.ExemplarTraceIDDestinations {
+		if d.Name == "" {
+			return fmt.Errorf("exemplar trace ID destination name is required")
+		}
+		if d.DatasourceUID == "" && d.URL == "" {
+			return fmt.Errorf("exemplar trace ID destination %s requires a datasource UID or a URL", d.Name)
+		}
+	}
+	return nil
+}
+
+func (s *PrometheusSettings) JSONDataMap() (map[string]interface{}, error) {
+	return structToMap(s)
+}
+
+func (s *PrometheusSettings) SecureJSONDataMap() (map[string]interface{}, error) {
+	return structToMap(s.Secure)
+}
+
+// LokiSettings are the settings of Loki datasources.
+type LokiSettings struct {
+	TLSSettings
+	MaxLines        int                `json:"maxLines,omitempty"`
+	DerivedFields   []LokiDerivedField `json:"derivedFields,omitempty"`
+	ManageAlerts    bool               `json:"manageAlerts,omitempty"`
+	AlertmanagerUID string             `json:"alertmanagerUid,omitempty"`
+
+	Secure HTTPSecureSettings `json:"-"`
+}
+
+func (s *LokiSettings) DataSourceType() string {
+	return DataSourceTypeLoki
+}
+
+func (s *LokiSettings) Validate() error {
+	if s.MaxLines < 0 {
+		return fmt.Errorf("maxLines must be positive, got %d", s.MaxLines)
+	}
+	for _, f := range s.DerivedFields {
+		if f.Name == "" || f.MatcherRegex == "" {
+			return fmt.Errorf("derived fields require a name and a matcher regex")
+		}
+		if _, err := regexp.Compile(f.MatcherRegex); err != nil {
+			return fmt.Errorf("invalid matcher regex for derived field %s: %w", f.Name, err)
+		}
+	}
+	return nil
+}
+
+func (s *LokiSettings) JSONDataMap() (map[string]interface{}, error) {
+	return structToMap(s)
+}
+
+func (s *LokiSettings) SecureJSONDataMap() (map[string]interface{}, error) {
+	return structToMap(s.Secure)
+}
+
+// TempoTag maps a span attribute to a label name.
+type TempoTag struct {
+	Key   string `json:"key"`
+	Value string `json:"value,omitempty"`
+}
+
+// TempoTracesToLogs links traces to logs in a Loki or Elasticsearch datasource.
+type TempoTracesToLogs struct {
+	DatasourceUID      string     `json:"datasourceUid"`
+	Tags               []TempoTag `json:"tags,omitempty"`
+	SpanStartTimeShift string     `json:"spanStartTimeShift,omitempty"`
+	SpanEndTimeShift   string     `json:"spanEndTimeShift,omitempty"`
+	FilterByTraceID    bool       `json:"filterByTraceID,omitempty"`
+	FilterBySpanID     bool       `json:"filterBySpanID,omitempty"`
+	CustomQuery        bool       `json:"customQuery,omitempty"`
+	Query              string     `json:"query,omitempty"`
+}
+
+// TempoDatasourceLink references another datasource used by a Tempo feature.
+type TempoDatasourceLink struct {
+	DatasourceUID string `json:"datasourceUid"`
+}
+
+// TempoNodeGraph toggles the node graph visualization of traces.
+type TempoNodeGraph struct {
+	Enabled bool `json:"enabled"`
+}
+
+// TempoSettings are the settings of Tempo datasources.
+type TempoSettings struct {
+	TLSSettings
+	TracesToLogs    *TempoTracesToLogs   `json:"tracesToLogsV2,omitempty"`
+	TracesToMetrics *TempoDatasourceLink `json:"tracesToMetrics,omitempty"`
+	ServiceMap      *TempoDatasourceLink `json:"serviceMap,omitempty"`
+	LokiSearch      *TempoDatasourceLink `json:"lokiSearch,omitempty"`
+	NodeGraph       *TempoNodeGraph      `json:"nodeGraph,omitempty"`
+
+	Secure HTTPSecureSettings `json:"-"`
+}
+
+func (s *TempoSettings) DataSourceType() string {
+	return DataSourceTypeTempo
+}
+
+func (s *TempoSettings) Validate() error {
+	if s.TracesToLogs != nil && s.TracesToLogs.DatasourceUID == "" {
+		return fmt.Errorf("traces to logs requires a datasource UID")
+	}
+	if s.TracesToMetrics != nil && s.TracesToMetrics.DatasourceUID == "" {
+		return fmt.Errorf("traces to metrics requires a datasource UID")
+	}
+	if s.ServiceMap != nil && s.ServiceMap.DatasourceUID == "" {
+		return fmt.Errorf("service map requires a datasource UID")
+	}
+	if s.LokiSearch != nil && s.LokiSearch.DatasourceUID == "" {
+		return fmt.Errorf("loki search requires a datasource UID")
+	}
+	return nil
+}
+
+func (s *TempoSettings) JSONDataMap() (map[string]interface{}, error) {
+	return structToMap(s)
+}
+
+func (s *TempoSettings) SecureJSONDataMap() (map[string]interface{}, error) {
+	return structToMap(s.Secure)
+}
+
+// ElasticsearchDataLink links a log field to a URL or to another datasource.
+type ElasticsearchDataLink struct {
+	Field         string `json:"field"`
+	URL           string `json:"url"`
+	DatasourceUID string `json:"datasourceUid,omitempty"`
+}
+
+// ElasticsearchSettings are the settings of Elasticsearch datasources.
+type ElasticsearchSettings struct {
+	TLSSettings
+	// Index is the index name or pattern. Grafana versions before 10 use DataSource.Database instead.
+	Index string `json:"index,omitempty"`
+	// EsVersion is the semantic version of Elasticsearch.
+	EsVersion                  string                  `json:"esVersion,omitempty"`
+	TimeField                  string                  `json:"timeField"`
+	Interval                   string                  `json:"interval,omitempty"`
+	TimeInterval               string                  `json:"timeInterval,omitempty"`
+	LogMessageField            string                  `json:"logMessageField,omitempty"`
+	LogLevelField              string                  `json:"logLevelField,omitempty"`
+	MaxConcurrentShardRequests int64                   `json:"maxConcurrentShardRequests,omitempty"`
+	IncludeFrozen              bool                    `json:"includeFrozen,omitempty"`
+	XpackEnabled               bool                    `json:"xpack,omitempty"`
+	DataLinks                  []ElasticsearchDataLink `json:"dataLinks,omitempty"`
+
+	Secure HTTPSecureSettings `json:"-"`
+}
+
+func (s *ElasticsearchSettings) DataSourceType() string {
+	return DataSourceTypeElasticsearch
+}
+
+func (s *ElasticsearchSettings) Validate() error {
+	if s.TimeField == "" {
+		return fmt.Errorf("timeField is required")
+	}
+	if err := validateOneOf("interval", s.Interval, "Hourly", "Daily", "Weekly", "Monthly", "Yearly"); err != nil {
+		return err
+	}
+	for _, l := range s.DataLinks {
+		if l.Field == "" {
+			return fmt.Errorf("data link field is required")
+		}
+	}
+	return nil
+}
+
+func (s *ElasticsearchSettings) JSONDataMap() (map[string]interface{}, error) {
+	return structToMap(s)
+}
+
+func (s *ElasticsearchSettings) SecureJSONDataMap() (map[string]interface{}, error) {
+	return structToMap(s.Secure)
+}
+
+const (
+	influxDBVersionFlux     = "Flux"
+	influxDBVersionInfluxQL = "InfluxQL"
+)
+
+// InfluxDBFluxSettings are the settings of InfluxDB datasources using the Flux query language.
+type InfluxDBFluxSettings struct {
+	TLSSettings
+	Organization  string `json:"organization"`
+	DefaultBucket string `json:"defaultBucket"`
+	TimeInterval  string `json:"timeInterval,omitempty"`
+	MaxSeries     int64  `json:"maxSeries,omitempty"`
+
+	Secure InfluxDBFluxSecureSettings `json:"-"`
+}
+
+// InfluxDBFluxSecureSettings are the secure settings of InfluxDB datasources using the Flux query language.
+type InfluxDBFluxSecureSettings struct {
+	TLSSecureSettings
+	Token string `json:"token,omitempty"`
+}
+
+func (s *InfluxDBFluxSettings) DataSourceType() string {
+	return DataSourceTypeInfluxDB
+}
+
+func (s *InfluxDBFluxSettings) Validate() error {
+	if s.Organization == "" {
+		return fmt.Errorf("organization is required")
+	}
+	if s.DefaultBucket == "" {
+		return fmt.Errorf("defaultBucket is required")
+	}
+	return nil
+}
+
+func (s *InfluxDBFluxSettings) JSONDataMap() (map[string]interface{}, error) {
+	m, err := structToMap(s)
+	if err != nil {
+		return nil, err
+	}
+	m["version"] = influxDBVersionFlux
+	return m, nil
+}
+
+func (s *InfluxDBFluxSettings) SecureJSONDataMap() (map[string]interface{}, error) {
+	return structToMap(s.Secure)
+}
+
+// InfluxDBInfluxQLSettings are the settings of InfluxDB datasources using the InfluxQL query language.
+type InfluxDBInfluxQLSettings struct {
+	TLSSettings
+	DBName       string `json:"dbName"`
+	HTTPMode     string `json:"httpMode,omitempty"`
+	TimeInterval string `json:"timeInterval,omitempty"`
+	MaxSeries    int64  `json:"maxSeries,omitempty"`
+
+	Secure InfluxDBInfluxQLSecureSettings `json:"-"`
+}
+
+// InfluxDBInfluxQLSecureSettings are the secure settings of InfluxDB datasources using the InfluxQL query language.
+type InfluxDBInfluxQLSecureSettings struct {
+	HTTPSecureSettings
+	Password string `json:"password,omitempty"`
+}
+
+func (s *InfluxDBInfluxQLSettings) DataSourceType() string {
+	return DataSourceTypeInfluxDB
+}
+
+func (s *InfluxDBInfluxQLSettings) Validate() error {
+	if s.DBName == "" {
+		return fmt.Errorf("dbName is required")
+	}
+	return validateOneOf("httpMode", s.HTTPMode, "GET", "POST")
+}
+
+func (s *InfluxDBInfluxQLSettings) JSONDataMap() (map[string]interface{}, error) {
+	m, err := structToMap(s)
+	if err != nil {
+		return nil, err
+	}
+	m["version"] = influxDBVersionInfluxQL
+	return m, nil
+}
+
+func (s *InfluxDBInfluxQLSettings) SecureJSONDataMap() (map[string]interface{}, error) {
+	return structToMap(s.Secure)
+}
+
+// SQLConnectionSettings are the settings shared by SQL datasources.
+type SQLConnectionSettings struct {
+	// Database is the database name. Grafana versions before 9 use DataSource.Database instead.
+	Database        string `json:"database"`
+	MaxOpenConns    int64  `json:"maxOpenConns,omitempty"`
+	MaxIdleConns    int64  `json:"maxIdleConns,omitempty"`
+	ConnMaxLifetime int64  `json:"connMaxLifetime,omitempty"`
+	TimeInterval    string `json:"timeInterval,omitempty"`
+}
+
+func (s SQLConnectionSettings) validate() error {
+	if s.Database == "" {
+		return fmt.Errorf("database is required")
+	}
+	if s.MaxOpenConns < 0 || s.MaxIdleConns < 0 || s.ConnMaxLifetime < 0 {
+		return fmt.Errorf("connection limits must be positive")
+	}
+	return nil
+}
+
+// SQLSecureSettings are the secure settings shared by SQL datasources.
+type SQLSecureSettings struct {
+	TLSSecureSettings
+	Password string `json:"password,omitempty"`
+}
+
+// PostgresSettings are the settings of PostgreSQL datasources.
+type PostgresSettings struct {
+	SQLConnectionSettings
+	Sslmode                string `json:"sslmode,omitempty"`
+	TLSConfigurationMethod string `json:"tlsConfigurationMethod,omitempty"`
+	PostgresVersion        int64  `json:"postgresVersion,omitempty"`
+	Timescaledb            bool   `json:"timescaledb,omitempty"`
+
+	// Type is the datasource type, DataSourceTypePostgres by default. Settings keeps the type of the datasource so
+	// that PostgreSQL datasources of Grafana 10.3+ keep the DataSourceTypePostgresPlugin type.
+	Type string `json:"-"`
+
+	Secure SQLSecureSettings `json:"-"`
+}
+
+func (s *PostgresSettings) DataSourceType() string {
+	return defaultString(s.Type, DataSourceTypePostgres)
+}
+
+func (s *PostgresSettings) Validate() error {
+	if err := validateOneOf("type", s.Type, DataSourceTypePostgres, DataSourceTypePostgresPlugin); err != nil {
+		return err
+	}
+	if err := s.SQLConnectionSettings.validate(); err != nil {
+		return err
+	}
+	if err := validateOneOf("tlsConfigurationMethod", s.TLSConfigurationMethod, "file-path", "file-content"); err != nil {
+		return err
+	}
+	return validateOneOf("sslmode", s.Sslmode, "disable", "require", "verify-ca", "verify-full")
+}
+
+func (s *PostgresSettings) JSONDataMap() (map[string]interface{}, error) {
+	return structToMap(s)
+}
+
+func (s *PostgresSettings) SecureJSONDataMap() (map[string]interface{}, error) {
+	return structToMap(s.Secure)
+}
+
+// MySQLSettings are the settings of MySQL datasources.
+type MySQLSettings struct {
+	SQLConnectionSettings
+	TLSSettings
+	Timezone string `json:"timezone,omitempty"`
+
+	Secure SQLSecureSettings `json:"-"`
+}
+
+func (s *MySQLSettings) DataSourceType() string {
+	return DataSourceTypeMySQL
+}
+
+func (s *MySQLSettings) Validate() error {
+	return s.SQLConnectionSettings.validate()
+}
+
+func (s *MySQLSettings) JSONDataMap() (map[string]interface{}, error) {
+	return structToMap(s)
+}
+
+func (s *MySQLSettings) SecureJSONDataMap() (map[string]interface{}, error) {
+	return structToMap(s.Secure)
+}
+
+// MSSQLSettings are the settings of Microsoft SQL Server datasources.
+type MSSQLSettings struct {
+	SQLConnectionSettings
+	Encrypt            string `json:"encrypt,omitempty"`
+	TLSSkipVerify      bool   `json:"tlsSkipVerify,omitempty"`
+	ServerName         string `json:"serverName,omitempty"`
+	AuthenticationType string `json:"authenticationType,omitempty"`
+
+	Secure SQLSecureSettings `json:"-"`
+}
+
+func (s *MSSQLSettings) DataSourceType() string {
+	return DataSourceTypeMSSQL
+}
+
+func (s *MSSQLSettings) Validate() error {
+	if err := s.SQLConnectionSettings.validate(); err != nil {
+		return err
+	}
+	return validateOneOf("encrypt", s.Encrypt, "disable", "false", "true")
+}
+
+func (s *MSSQLSettings) JSONDataMap() (map[string]interface{}, error) {
+	return structToMap(s)
+}
+
+func (s *MSSQLSettings) SecureJSONDataMap() (map[string]interface{}, error) {
+	return structToMap(s.Secure)
+}
+
+// CloudWatchSettings are the settings of CloudWatch datasources.
+type CloudWatchSettings struct {
+	AuthType                string `json:"authType,omitempty"`
+	DefaultRegion           string `json:"defaultRegion"`
+	AssumeRoleArn           string `json:"assumeRoleArn,omitempty"`
+	ExternalID              string `json:"externalId,omitempty"`
+	Endpoint                string `json:"endpoint,omitempty"`
+	Profile                 string `json:"profile,omitempty"`
+	CustomMetricsNamespaces string `json:"customMetricsNamespaces,omitempty"`
+	TracingDatasourceUID    string `json:"tracingDatasourceUid,omitempty"`
+	LogsTimeout             string `json:"logsTimeout,omitempty"`
+
+	Secure CloudWatchSecureSettings `json:"-"`
+}
+
+// CloudWatchSecureSettings are the secure settings of CloudWatch datasources.
+type CloudWatchSecureSettings struct {
+	AccessKey string `json:"accessKey,omitempty"`
+	SecretKey string `json:"secretKey,omitempty"`
+}
+
+func (s *CloudWatchSettings) DataSourceType() string {
+	return DataSourceTypeCloudWatch
+}
+
+func (s *CloudWatchSettings) Validate() error {
+	if s.DefaultRegion == "" {
+		return fmt.Errorf("defaultRegion is required")
+	}
+	return validateOneOf("authType", s.AuthType, "default", "keys", "credentials", "ec2_iam_role", "grafana_assume_role")
+}
+
+func (s *CloudWatchSettings) JSONDataMap() (map[string]interface{}, error) {
+	return structToMap(s)
+}
+
+func (s *CloudWatchSettings) SecureJSONDataMap() (map[string]interface{}, error) {
+	return structToMap(s.Secure)
+}
+
+// AzureMonitorSettings are the settings of Azure Monitor datasources.
+type AzureMonitorSettings struct {
+	CloudName      string `json:"cloudName,omitempty"`
+	AzureAuthType  string `json:"azureAuthType,omitempty"`
+	TenantID       string `json:"tenantId,omitempty"`
+	ClientID       string `json:"clientId,omitempty"`
+	SubscriptionID string `json:"subscriptionId,omitempty"`
+
+	Secure AzureMonitorSecureSettings `json:"-"`
+}
+
+// AzureMonitorSecureSettings are the secure settings of Azure Monitor datasources.
+type AzureMonitorSecureSettings struct {
+	ClientSecret string `json:"clientSecret,omitempty"`
+}
+
+func (s *AzureMonitorSettings) DataSourceType() string {
+	return DataSourceTypeAzureMonitor
+}
+
+func (s *AzureMonitorSettings) Validate() error {
+	if err := validateOneOf("azureAuthType", s.AzureAuthType, "clientsecret", "msi", "workloadidentity"); err != nil {
+		return err
+	}
+	if err := validateOneOf("cloudName", s.CloudName, "azuremonitor", "chinaazuremonitor", "govazuremonitor"); err != nil {
+		return err
+	}
+	if s.AzureAuthType == "" || s.AzureAuthType == "clientsecret" {
+		if s.TenantID == "" || s.ClientID == "" {
+			return fmt.Errorf("tenantId and clientId are required for client secret authentication")
+		}
+	}
+	return nil
+}
+
+func (s *AzureMonitorSettings) JSONDataMap() (map[string]interface{}, error) {
+	return structToMap(s)
+}
+
+func (s *AzureMonitorSettings) SecureJSONDataMap() (map[string]interface{}, error) {
+	return structToMap(s.Secure)
+}
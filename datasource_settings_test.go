@@ -0,0 +1,152 @@
+package gapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gobs/pretty"
+)
+
+func TestDataSourceSetSettings(t *testing.T) {
+	ds := &DataSource{Name: "prometheus", URL: "http://prometheus:9090"}
+	settings := &PrometheusSettings{
+		TLSSettings: TLSSettings{TLSSkipVerify: true},
+		HTTPMethod:  "POST",
+		ExemplarTraceIDDestinations: []PrometheusExemplarTraceIDDestination{
+			{Name: "traceID", DatasourceUID: "tempo"},
+		},
+		Secure: HTTPSecureSettings{BasicAuthPassword: "secret"},
+	}
+
+	if err := ds.SetSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(pretty.PrettyFormat(ds))
+
+	if ds.Type != "prometheus" {
+		t.Errorf("Unexpected type: %s", ds.Type)
+	}
+	if ds.JSONData["httpMethod"] != "POST" || ds.JSONData["tlsSkipVerify"] != true {
+		t.Errorf("Unexpected jsonData: %v", ds.JSONData)
+	}
+	if _, ok := ds.JSONData["Secure"]; ok {
+		t.Error("Secure settings should not be part of jsonData.")
+	}
+	if len(ds.SecureJSONData) != 1 || ds.SecureJSONData["basicAuthPassword"] != "secret" {
+		t.Errorf("Unexpected secureJsonData: %v", ds.SecureJSONData)
+	}
+}
+
+func TestDataSourceSetSettingsInvalid(t *testing.T) {
+	cases := map[string]DataSourceSettings{
+		"prometheus http method":    &PrometheusSettings{HTTPMethod: "PUT"},
+		"loki derived field regex":  &LokiSettings{DerivedFields: []LokiDerivedField{{Name: "traceID", MatcherRegex: "("}}},
+		"tempo traces to logs":      &TempoSettings{TracesToLogs: &TempoTracesToLogs{}},
+		"elasticsearch time field":  &ElasticsearchSettings{},
+		"influxdb flux bucket":      &InfluxDBFluxSettings{Organization: "org"},
+		"influxdb influxql db name": &InfluxDBInfluxQLSettings{},
+		"postgres sslmode":          &PostgresSettings{SQLConnectionSettings: SQLConnectionSettings{Database: "db"}, Sslmode: "prefer"},
+		"postgres type":             &PostgresSettings{SQLConnectionSettings: SQLConnectionSettings{Database: "db"}, Type: "mysql"},
+		"mysql database":            &MySQLSettings{},
+		"mssql encrypt":             &MSSQLSettings{SQLConnectionSettings: SQLConnectionSettings{Database: "db"}, Encrypt: "maybe"},
+		"cloudwatch region":         &CloudWatchSettings{},
+		"azure monitor client":      &AzureMonitorSettings{TenantID: "tenant"},
+	}
+
+	for name, settings := range cases {
+		t.Run(name, func(t *testing.T) {
+			ds := &DataSource{}
+			if err := ds.SetSettings(settings); err == nil {
+				t.Error("Expected a validation error.")
+			}
+		})
+	}
+}
+
+func TestInfluxDBSettingsVersion(t *testing.T) {
+	ds := &DataSource{}
+	err := ds.SetSettings(&InfluxDBFluxSettings{
+		Organization:  "org",
+		DefaultBucket: "bucket",
+		Secure:        InfluxDBFluxSecureSettings{Token: "token"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ds.JSONData["version"] != "Flux" || ds.SecureJSONData["token"] != "token" {
+		t.Errorf("Unexpected datasource settings: %v %v", ds.JSONData, ds.SecureJSONData)
+	}
+
+	settings, err := ds.Settings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	flux, ok := settings.(*InfluxDBFluxSettings)
+	if !ok || flux.DefaultBucket != "bucket" {
+		t.Errorf("Unexpected decoded settings: %s", pretty.PrettyFormat(settings))
+	}
+}
+
+func TestDataSourceSettings(t *testing.T) {
+	ds := &DataSource{}
+	err := json.Unmarshal([]byte(`{
+		"type": "postgres",
+		"jsonData": {
+			"database": "grafana",
+			"sslmode": "verify-full",
+			"maxOpenConns": 10,
+			"timescaledb": true
+		}
+	}`), ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	settings, err := ds.Settings()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(pretty.PrettyFormat(settings))
+
+	postgres, ok := settings.(*PostgresSettings)
+	if !ok {
+		t.Fatalf("Expected postgres settings, got %T", settings)
+	}
+	if postgres.Database != "grafana" || postgres.Sslmode != "verify-full" || postgres.MaxOpenConns != 10 || !postgres.Timescaledb {
+		t.Error("Not correctly decoding datasource settings.")
+	}
+	if err := postgres.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := (&DataSource{Type: "graphite"}).Settings(); err == nil {
+		t.Error("Expected an error for a datasource type without typed settings.")
+	}
+}
+
+func TestPostgresPluginSettingsRoundTrip(t *testing.T) {
+	ds := &DataSource{
+		Type:     DataSourceTypePostgresPlugin,
+		JSONData: map[string]interface{}{"database": "grafana", "sslmode": "disable"},
+	}
+
+	settings, err := ds.Settings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.SetSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+
+	if ds.Type != DataSourceTypePostgresPlugin {
+		t.Errorf("Expected the datasource type to be kept, got %s", ds.Type)
+	}
+	if ds.JSONData["database"] != "grafana" || ds.JSONData["sslmode"] != "disable" {
+		t.Errorf("Unexpected jsonData: %v", ds.JSONData)
+	}
+	if _, ok := ds.JSONData["Type"]; ok {
+		t.Error("The type should not be part of jsonData.")
+	}
+}
@@ -1,15 +1,18 @@
 package gapi
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/gobs/pretty"
 )
 
 const (
-	createdDataSourceJSON = `{"id":1,"uid":"myuid0001","message":"Datasource added", "name": "test_datasource"}`
-	getDataSourceJSON     = `{"id":1}`
-	getDataSourcesJSON    = `[{"id":1,"name":"foo","type":"cloudwatch","url":"http://some-url.com","access":"access","isDefault":true}]`
+	createdDataSourceJSON  = `{"id":1,"uid":"myuid0001","message":"Datasource added", "name": "test_datasource"}`
+	getDataSourceJSON      = `{"id":1}`
+	getDataSourcesJSON     = `[{"id":1,"name":"foo","type":"cloudwatch","url":"http://some-url.com","access":"access","isDefault":true}]`
+	getPrometheusJSON      = `{"id":3,"uid":"prom","orgId":1,"name":"prometheus","type":"prometheus","url":"http://prometheus:9090","access":"proxy","isDefault":false,"basicAuth":false,"jsonData":{"httpMethod":"POST","timeout":30},"readOnly":false}`
+	notFoundDataSourceJSON = `{"message":"Data source not found"}`
 )
 
 func TestNewDataSource(t *testing.T) {
@@ -317,3 +320,157 @@ func TestDataSourceIDByName(t *testing.T) {
 		t.Error("Not correctly parsing returned datasources.")
 	}
 }
+
+func TestDataSourceByName(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, getPrometheusJSON}})
+	defer server.Close()
+
+	ds, err := client.DataSourceByName("prometheus")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(pretty.PrettyFormat(ds))
+
+	if ds.UID != "prom" || ds.Type != "prometheus" {
+		t.Error("Not correctly parsing returned data source.")
+	}
+	if path := server.receivedRequests[0].path; path != "/api/datasources/name/prometheus" {
+		t.Errorf("Unexpected request path: %s", path)
+	}
+}
+
+func TestUpsertDataSource(t *testing.T) {
+	desired := func() *DataSource {
+		return &DataSource{
+			Name:     "prometheus",
+			Type:     "prometheus",
+			URL:      "http://prometheus:9090",
+			Access:   "proxy",
+			JSONData: map[string]interface{}{"httpMethod": "POST", "timeout": 30},
+		}
+	}
+
+	t.Run("created", func(t *testing.T) {
+		server, client := gapiTestToolsFromCalls(t, []mockServerCall{
+			{404, notFoundDataSourceJSON},
+			{200, createdDataSourceJSON},
+		})
+		defer server.Close()
+
+		ds := desired()
+		result, err := client.UpsertDataSource(ds)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result != DataSourceCreated || ds.ID != 1 {
+			t.Errorf("Unexpected result %s with ID %d", result, ds.ID)
+		}
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, getPrometheusJSON}})
+		defer server.Close()
+
+		ds := desired()
+		result, err := client.UpsertDataSource(ds)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result != DataSourceUnchanged || ds.ID != 3 || ds.UID != "prom" {
+			t.Errorf("Unexpected result %s with ID %d and UID %s", result, ds.ID, ds.UID)
+		}
+	})
+
+	t.Run("updated by UID", func(t *testing.T) {
+		server, client := gapiTestToolsFromCalls(t, []mockServerCall{
+			{200, getPrometheusJSON},
+			{200, `{"message":"Datasource updated"}`},
+		})
+		defer server.Close()
+
+		ds := desired()
+		ds.UID = "prom"
+		ds.SecureJSONData = map[string]interface{}{"httpHeaderValue1": "token"}
+		result, err := client.UpsertDataSource(ds)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result != DataSourceUpdated {
+			t.Errorf("Unexpected result %s", result)
+		}
+		if get := server.receivedRequests[0]; get.path != "/api/datasources/uid/prom" {
+			t.Errorf("Unexpected lookup request: %s", get.path)
+		}
+		if update := server.receivedRequests[1]; update.method != "PUT" || update.path != "/api/datasources/3" {
+			t.Errorf("Unexpected update request: %s %s", update.method, update.path)
+		}
+	})
+
+	t.Run("lookup error", func(t *testing.T) {
+		server, client := gapiTestToolsFromCalls(t, []mockServerCall{{403, `{"message":"Permission denied"}`}})
+		defer server.Close()
+
+		if _, err := client.UpsertDataSource(desired()); err == nil {
+			t.Error("Expected an error when the lookup fails.")
+		}
+	})
+}
+
+func TestDataSourceSecureJSONFields(t *testing.T) {
+	server, client := gapiTestTools(t, 200, `{"id":3,"uid":"prom","name":"prometheus","type":"prometheus","secureJsonFields":{"basicAuthPassword":true,"httpHeaderValue1":true}}`)
+	defer server.Close()
+
+	ds, err := client.DataSourceByUID("prom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ds.SecureJSONFields["basicAuthPassword"] || ds.SecureJSONFields["tlsClientKey"] {
+		t.Errorf("Not correctly parsing secure JSON fields: %v", ds.SecureJSONFields)
+	}
+}
+
+func TestResetDataSourceSecureFields(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{
+		{200, getPrometheusJSON},
+		{200, `{"message":"Datasource updated"}`},
+	})
+	defer server.Close()
+
+	if err := client.ResetDataSourceSecureFields("prom", "basicAuthPassword"); err != nil {
+		t.Fatal(err)
+	}
+
+	update := server.receivedRequests[1]
+	if update.method != "PUT" || update.path != "/api/datasources/uid/prom" {
+		t.Errorf("Unexpected update request: %s %s", update.method, update.path)
+	}
+	var body DataSource
+	if err := json.Unmarshal([]byte(update.body), &body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.SecureJSONData) != 1 || body.SecureJSONData["basicAuthPassword"] != "" || body.URL != "http://prometheus:9090" {
+		t.Errorf("Unexpected update body: %s", update.body)
+	}
+}
+
+func TestJSONDataWithHeaders(t *testing.T) {
+	jd, sjd := JSONDataWithHeaders(nil, nil, map[string]string{
+		"X-Scope-OrgID": "tenant",
+		"Authorization": "Bearer old",
+	})
+	if jd["httpHeaderName1"] != "Authorization" || jd["httpHeaderName2"] != "X-Scope-OrgID" || sjd["httpHeaderValue1"] != "Bearer old" {
+		t.Errorf("Unexpected headers: %v %v", jd, sjd)
+	}
+
+	// Rotating a single header keeps its index and only sends its value.
+	jd, sjd = JSONDataWithHeaders(jd, nil, map[string]string{"Authorization": "Bearer new"})
+	if len(sjd) != 1 || sjd["httpHeaderValue1"] != "Bearer new" || jd["httpHeaderName2"] != "X-Scope-OrgID" {
+		t.Errorf("Unexpected rotated headers: %v %v", jd, sjd)
+	}
+
+	jd, sjd = JSONDataWithHeaders(jd, nil, map[string]string{"X-Custom": "value"})
+	if jd["httpHeaderName3"] != "X-Custom" || sjd["httpHeaderValue3"] != "value" {
+		t.Errorf("Unexpected added header: %v %v", jd, sjd)
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
 )
 
 // Folder represents a Grafana folder.
@@ -12,23 +15,52 @@ type Folder struct {
 	UID   string `json:"uid"`
 	Title string `json:"title"`
 	URL   string `json:"url"`
+
+	// ParentUID is only set for nested folders. It is empty for folders at the root level.
+	ParentUID string `json:"parentUid,omitempty"`
 }
 
 type FolderPayload struct {
 	Title     string `json:"title"`
 	UID       string `json:"uid,omitempty"`
+	ParentUID string `json:"parentUid,omitempty"`
 	Overwrite bool   `json:"overwrite,omitempty"`
 }
 
 // Folders fetches and returns Grafana folders.
+// When nested folders are enabled, only the root level folders are returned.
 func (c *Client) Folders() ([]Folder, error) {
+	return c.folders(url.Values{})
+}
+
+// folders fetches every page of the folders matching the given query parameters.
+func (c *Client) folders(params url.Values) ([]Folder, error) {
+	const limit = 1000
 	folders := make([]Folder, 0)
-	err := c.request("GET", "/api/folders/", nil, nil, &folders)
-	if err != nil {
-		return folders, err
-	}
+	seen := make(map[string]bool)
+	for page := 1; ; page++ {
+		params.Set("limit", strconv.Itoa(limit))
+		params.Set("page", strconv.Itoa(page))
+
+		pageFolders := make([]Folder, 0)
+		err := c.request("GET", "/api/folders/", params, nil, &pageFolders)
+		if err != nil {
+			return folders, err
+		}
 
-	return folders, err
+		added := 0
+		for _, folder := range pageFolders {
+			if !seen[folder.UID] {
+				seen[folder.UID] = true
+				folders = append(folders, folder)
+				added++
+			}
+		}
+		// A server ignoring the page parameter returns the same folders again.
+		if len(pageFolders) < limit || added == 0 {
+			return folders, nil
+		}
+	}
 }
 
 // Folder fetches and returns the Grafana folder whose ID it's passed.
@@ -59,13 +91,37 @@ func (c *Client) NewFolder(title string, uid ...string) (Folder, error) {
 		return Folder{}, fmt.Errorf("too many arguments. Expected 1 or 2")
 	}
 
-	folder := Folder{}
 	payload := FolderPayload{
 		Title: title,
 	}
 	if len(uid) == 1 {
 		payload.UID = uid[0]
 	}
+
+	return c.newFolder(payload)
+}
+
+// NewNestedFolder creates a new Grafana folder inside the folder whose UID it's passed.
+// Nested folders require Grafana 10+ with the nestedFolders feature enabled, otherwise the folder is created at
+// the root level and an error is returned along with it.
+func (c *Client) NewNestedFolder(title string, parentUID string, uid ...string) (Folder, error) {
+	if len(uid) > 1 {
+		return Folder{}, fmt.Errorf("too many arguments. Expected 2 or 3")
+	}
+
+	payload := FolderPayload{
+		Title:     title,
+		ParentUID: parentUID,
+	}
+	if len(uid) == 1 {
+		payload.UID = uid[0]
+	}
+
+	return c.newFolder(payload)
+}
+
+func (c *Client) newFolder(payload FolderPayload) (Folder, error) {
+	folder := Folder{}
 	data, err := json.Marshal(payload)
 	if err != nil {
 		return folder, err
@@ -76,7 +132,12 @@ func (c *Client) NewFolder(title string, uid ...string) (Folder, error) {
 		return folder, err
 	}
 
-	return folder, err
+	// Grafana versions without nested folders ignore the parentUid and create the folder at the root level.
+	if folder.ParentUID != payload.ParentUID {
+		return folder, fmt.Errorf("folder %s was created under %q instead of %q, nested folders may not be enabled", folder.UID, folder.ParentUID, payload.ParentUID)
+	}
+
+	return folder, nil
 }
 
 // UpdateFolder updates the folder whose UID it's passed.
@@ -96,7 +157,210 @@ func (c *Client) UpdateFolder(uid string, title string, newUID ...string) error
 	return c.request("PUT", fmt.Sprintf("/api/folders/%s", uid), nil, bytes.NewBuffer(data), nil)
 }
 
+// FolderCounts represents the content stored in a Grafana folder and its subfolders.
+type FolderCounts struct {
+	Folders       int64 `json:"folder"`
+	Dashboards    int64 `json:"dashboard"`
+	LibraryPanels int64 `json:"librarypanel"`
+	AlertRules    int64 `json:"alertrule"`
+}
+
+// IsEmpty returns true if the folder contains no subfolder, dashboard, library panel or alert rule.
+func (fc FolderCounts) IsEmpty() bool {
+	return fc.Folders == 0 && fc.Dashboards == 0 && fc.LibraryPanels == 0 && fc.AlertRules == 0
+}
+
 // DeleteFolder deletes the folder whose ID it's passed.
+// Everything stored in the folder is deleted along with it, use SafeDeleteFolder to avoid that.
 func (c *Client) DeleteFolder(id string) error {
 	return c.request("DELETE", fmt.Sprintf("/api/folders/%s", id), nil, nil, nil)
 }
+
+// FolderCounts fetches and returns the number of subfolders, dashboards, library panels and alert rules
+// stored in the folder whose UID it's passed.
+func (c *Client) FolderCounts(uid string) (*FolderCounts, error) {
+	counts := &FolderCounts{}
+	err := c.request("GET", fmt.Sprintf("/api/folders/%s/counts", uid), nil, nil, counts)
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// SafeDeleteFolder deletes the folder whose UID it's passed only if it is empty.
+// It returns an error describing the folder content otherwise.
+func (c *Client) SafeDeleteFolder(uid string) error {
+	counts, err := c.FolderCounts(uid)
+	if err != nil {
+		return err
+	}
+	if !counts.IsEmpty() {
+		return fmt.Errorf(
+			"folder %s is not empty: %d folders, %d dashboards, %d library panels, %d alert rules",
+			uid, counts.Folders, counts.Dashboards, counts.LibraryPanels, counts.AlertRules,
+		)
+	}
+
+	return c.DeleteFolder(uid)
+}
+
+// CascadeDeleteFolder deletes the folder whose UID it's passed along with everything stored in it.
+// Grafana refuses to delete a folder containing alert rules unless forceDeleteRules is set.
+func (c *Client) CascadeDeleteFolder(uid string, forceDeleteRules bool) error {
+	params := url.Values{}
+	if forceDeleteRules {
+		params.Set("forceDeleteRules", "true")
+	}
+
+	return c.request("DELETE", fmt.Sprintf("/api/folders/%s", uid), params, nil, nil)
+}
+
+// MoveFolder moves the folder whose UID it's passed under the given parent folder.
+// An empty parentUID moves the folder to the root level.
+func (c *Client) MoveFolder(uid string, parentUID string) (*Folder, error) {
+	payload := struct {
+		ParentUID string `json:"parentUid"`
+	}{
+		ParentUID: parentUID,
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	folder := &Folder{}
+	err = c.request("POST", fmt.Sprintf("/api/folders/%s/move", uid), nil, bytes.NewBuffer(data), folder)
+	if err != nil {
+		return nil, err
+	}
+
+	return folder, nil
+}
+
+// ChildFolders fetches and returns the direct subfolders of the folder whose UID it's passed.
+func (c *Client) ChildFolders(parentUID string) ([]Folder, error) {
+	folders, err := c.folders(url.Values{
+		"parentUid": {parentUID},
+	})
+	if err != nil {
+		return folders, err
+	}
+
+	// Grafana versions without nested folders ignore the parentUid parameter and return the root level folders.
+	children := make([]Folder, 0, len(folders))
+	for _, folder := range folders {
+		if folder.ParentUID == parentUID {
+			children = append(children, folder)
+		}
+	}
+
+	return children, nil
+}
+
+// FolderByTitle fetches and returns the root level Grafana folder whose title it's passed.
+func (c *Client) FolderByTitle(title string) (*Folder, error) {
+	folder, err := c.childFolderByTitle("", title)
+	if err != nil {
+		return nil, err
+	}
+	if folder == nil {
+		return nil, fmt.Errorf("folder with title %s not found", title)
+	}
+
+	return folder, nil
+}
+
+// FolderByPath fetches and returns the Grafana folder at the given slash separated path of folder titles,
+// e.g. "team/a/b". Each segment after the first one is looked up among the subfolders of the previous one.
+func (c *Client) FolderByPath(folderPath string) (*Folder, error) {
+	titles, err := splitFolderPath(folderPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var folder *Folder
+	parentUID := ""
+	for i, title := range titles {
+		folder, err = c.childFolderByTitle(parentUID, title)
+		if err != nil {
+			return nil, err
+		}
+		if folder == nil {
+			return nil, fmt.Errorf("folder %s not found", strings.Join(titles[:i+1], "/"))
+		}
+		parentUID = folder.UID
+	}
+
+	return folder, nil
+}
+
+// EnsureFolder returns the Grafana folder at the given slash separated path of folder titles,
+// creating any missing folder along the way. Existing folders are left untouched.
+func (c *Client) EnsureFolder(folderPath string) (*Folder, error) {
+	titles, err := splitFolderPath(folderPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var folder *Folder
+	parentUID := ""
+	for _, title := range titles {
+		folder, err = c.childFolderByTitle(parentUID, title)
+		if err != nil {
+			return nil, err
+		}
+		if folder == nil {
+			created, err := c.newFolder(FolderPayload{
+				Title:     title,
+				ParentUID: parentUID,
+			})
+			if err != nil {
+				return nil, err
+			}
+			folder = &created
+		}
+		parentUID = folder.UID
+	}
+
+	return folder, nil
+}
+
+// childFolderByTitle returns the folder with the given title directly under parentUID,
+// or nil if there is none. An empty parentUID looks up root level folders.
+func (c *Client) childFolderByTitle(parentUID string, title string) (*Folder, error) {
+	var (
+		folders []Folder
+		err     error
+	)
+	if parentUID == "" {
+		folders, err = c.Folders()
+	} else {
+		folders, err = c.ChildFolders(parentUID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range folders {
+		if folders[i].Title == title {
+			return &folders[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+func splitFolderPath(folderPath string) ([]string, error) {
+	titles := make([]string, 0)
+	for _, title := range strings.Split(folderPath, "/") {
+		if title = strings.TrimSpace(title); title != "" {
+			titles = append(titles, title)
+		}
+	}
+	if len(titles) == 0 {
+		return nil, fmt.Errorf("invalid folder path %q", folderPath)
+	}
+
+	return titles, nil
+}
@@ -2,6 +2,7 @@ package gapi
 
 import (
 	"net/url"
+	"strconv"
 )
 
 // FolderDashboardSearchResponse represents the Grafana API dashboard search response.
@@ -27,3 +28,32 @@ func (c *Client) FolderDashboardSearch(params url.Values) (resp []FolderDashboar
 	err = c.request("GET", "/api/search", params, nil, &resp)
 	return
 }
+
+// folderDashboardSearchAll fetches every page of the search results matching the given query parameters.
+func (c *Client) folderDashboardSearchAll(params url.Values) ([]FolderDashboardSearchResponse, error) {
+	const limit = 1000
+	results := make([]FolderDashboardSearchResponse, 0)
+	seen := make(map[string]bool)
+	for page := 1; ; page++ {
+		params.Set("limit", strconv.Itoa(limit))
+		params.Set("page", strconv.Itoa(page))
+
+		pageResults, err := c.FolderDashboardSearch(params)
+		if err != nil {
+			return nil, err
+		}
+
+		added := 0
+		for _, result := range pageResults {
+			if !seen[result.UID] {
+				seen[result.UID] = true
+				results = append(results, result)
+				added++
+			}
+		}
+		// A server ignoring the page parameter returns the same results again.
+		if len(pageResults) < limit || added == 0 {
+			return results, nil
+		}
+	}
+}
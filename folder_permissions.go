@@ -21,6 +21,8 @@ type FolderPermission struct {
 	// 4 = Admin
 	Permission     int64  `json:"permission"`
 	PermissionName string `json:"permissionName"`
+	// Inherited is set for permissions inherited from a parent folder.
+	Inherited bool `json:"inherited"`
 
 	// optional fields
 	FolderID    int64 `json:"folderId,omitempty"`
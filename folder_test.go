@@ -1,6 +1,8 @@
 package gapi
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/gobs/pretty"
@@ -81,6 +83,40 @@ const (
 {
   "message":"Folder deleted"
 }
+`
+	nestedFolderJSON = `
+{
+  "id":2,
+  "uid": "k3S1cklGk",
+  "title": "Team A",
+  "url": "/dashboards/f/k3S1cklGk/team-a",
+  "parentUid": "nErXDvCkzz",
+  "version": 1
+}
+`
+	folderCountsJSON = `
+{
+  "folder": 1,
+  "dashboard": 2,
+  "librarypanel": 0,
+  "alertrule": 3
+}
+`
+	childFoldersJSON = `
+[
+  {
+    "id":2,
+    "uid": "k3S1cklGk",
+    "title": "Team A",
+    "parentUid": "nErXDvCkzz"
+  },
+  {
+    "id":3,
+    "uid": "c6hy1ogGz",
+    "title": "Team B",
+    "parentUid": "nErXDvCkzz"
+  }
+]
 `
 )
 
@@ -172,3 +208,232 @@ func TestDeleteFolder(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestNewNestedFolder(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, nestedFolderJSON}})
+	defer server.Close()
+
+	resp, err := client.NewNestedFolder("Team A", "nErXDvCkzz")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(pretty.PrettyFormat(resp))
+
+	if resp.UID != "k3S1cklGk" || resp.ParentUID != "nErXDvCkzz" {
+		t.Error("Not correctly parsing returned creation message.")
+	}
+	if body := server.receivedRequests[0].body; body != `{"title":"Team A","parentUid":"nErXDvCkzz"}` {
+		t.Errorf("Unexpected request body: %s", body)
+	}
+}
+
+func TestMoveFolder(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, nestedFolderJSON}})
+	defer server.Close()
+
+	resp, err := client.MoveFolder("k3S1cklGk", "nErXDvCkzz")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.ParentUID != "nErXDvCkzz" {
+		t.Error("Not correctly parsing returned folder.")
+	}
+	if req := server.receivedRequests[0]; req.method != "POST" || req.path != "/api/folders/k3S1cklGk/move" {
+		t.Errorf("Unexpected request: %s %s", req.method, req.path)
+	}
+}
+
+func TestChildFolders(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, childFoldersJSON}})
+	defer server.Close()
+
+	folders, err := client.ChildFolders("nErXDvCkzz")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(pretty.PrettyFormat(folders))
+
+	if len(folders) != 2 {
+		t.Error("Length of returned folders should be 2")
+	}
+	if parent := server.receivedRequests[0].query.Get("parentUid"); parent != "nErXDvCkzz" {
+		t.Errorf("Unexpected parentUid query parameter: %s", parent)
+	}
+}
+
+func TestFoldersPagination(t *testing.T) {
+	firstPage := make([]string, 1000)
+	for i := range firstPage {
+		firstPage[i] = fmt.Sprintf(`{"id": %d, "uid": "uid-%d", "title": "folder %d"}`, i+1, i+1, i+1)
+	}
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{
+		{200, "[" + strings.Join(firstPage, ",") + "]"},
+		{200, `[{"id": 1001, "uid": "uid-1001", "title": "folder 1001"}]`},
+	})
+	defer server.Close()
+
+	folders, err := client.Folders()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(folders) != 1001 {
+		t.Errorf("Length of returned folders should be 1001, got %d", len(folders))
+	}
+	if page := server.receivedRequests[1].query.Get("page"); page != "2" {
+		t.Errorf("Expected second request to fetch page 2, got %s", page)
+	}
+}
+
+func TestFoldersPaginationIgnoredPage(t *testing.T) {
+	firstPage := make([]string, 1000)
+	for i := range firstPage {
+		firstPage[i] = fmt.Sprintf(`{"id": %d, "uid": "uid-%d", "title": "folder %d"}`, i+1, i+1, i+1)
+	}
+	page := "[" + strings.Join(firstPage, ",") + "]"
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, page}, {200, page}})
+	defer server.Close()
+
+	folders, err := client.Folders()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(folders) != 1000 {
+		t.Errorf("Length of returned folders should be 1000, got %d", len(folders))
+	}
+	if len(server.receivedRequests) != 2 {
+		t.Errorf("Expected 2 requests, got %d", len(server.receivedRequests))
+	}
+}
+
+func TestFolderByTitle(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, getFoldersJSON}, {200, getFoldersJSON}})
+	defer server.Close()
+
+	folder, err := client.FolderByTitle("Departmenet ABC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if folder.UID != "nErXDvCkzz" {
+		t.Error("Not correctly finding folder by title.")
+	}
+
+	_, err = client.FolderByTitle("missing")
+	if err == nil || err.Error() != "folder with title missing not found" {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+}
+
+func TestFolderByPath(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, getFoldersJSON}, {200, childFoldersJSON}})
+	defer server.Close()
+
+	folder, err := client.FolderByPath("Departmenet ABC/Team B")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if folder.UID != "c6hy1ogGz" {
+		t.Error("Not correctly finding folder by path.")
+	}
+	if parent := server.receivedRequests[1].query.Get("parentUid"); parent != "nErXDvCkzz" {
+		t.Errorf("Unexpected parentUid query parameter: %s", parent)
+	}
+}
+
+func TestEnsureFolder(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{
+		{200, getFoldersJSON},
+		{200, "[]"},
+		{200, nestedFolderJSON},
+	})
+	defer server.Close()
+
+	folder, err := client.EnsureFolder("/Departmenet ABC/Team A/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if folder.UID != "k3S1cklGk" {
+		t.Error("Not correctly returning created folder.")
+	}
+
+	create := server.receivedRequests[2]
+	if create.method != "POST" || create.body != `{"title":"Team A","parentUid":"nErXDvCkzz"}` {
+		t.Errorf("Unexpected create request: %s %s", create.method, create.body)
+	}
+}
+
+func TestEnsureFolderWithoutNestedFolders(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{
+		{200, getFoldersJSON},
+		{200, "[]"},
+		{200, `{"id": 2, "uid": "k3S1cklGk", "title": "Team A"}`},
+	})
+	defer server.Close()
+
+	if _, err := client.EnsureFolder("Departmenet ABC/Team A/Project"); err == nil {
+		t.Error("Expected an error when the folder is created at the root level.")
+	}
+	if len(server.receivedRequests) != 3 {
+		t.Errorf("Expected no request after the misplaced folder creation, got %d requests", len(server.receivedRequests))
+	}
+}
+
+func TestEnsureFolderInvalidPath(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, nil)
+	defer server.Close()
+
+	if _, err := client.EnsureFolder(" / "); err == nil {
+		t.Error("Expected an error for an empty folder path.")
+	}
+}
+
+func TestFolderCounts(t *testing.T) {
+	server, client := gapiTestTools(t, 200, folderCountsJSON)
+	defer server.Close()
+
+	counts, err := client.FolderCounts("nErXDvCkzz")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(pretty.PrettyFormat(counts))
+
+	if counts.Folders != 1 || counts.Dashboards != 2 || counts.LibraryPanels != 0 || counts.AlertRules != 3 {
+		t.Error("Not correctly parsing returned folder counts.")
+	}
+}
+
+func TestSafeDeleteFolder(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{
+		{200, folderCountsJSON},
+		{200, `{"folder": 0, "dashboard": 0, "librarypanel": 0, "alertrule": 0}`},
+		{200, deletedFolderJSON},
+	})
+	defer server.Close()
+
+	if err := client.SafeDeleteFolder("nErXDvCkzz"); err == nil {
+		t.Error("Expected an error when deleting a folder with content.")
+	}
+	if err := client.SafeDeleteFolder("nErXDvCkzz"); err != nil {
+		t.Fatal(err)
+	}
+	if len(server.receivedRequests) != 3 || server.receivedRequests[2].method != "DELETE" {
+		t.Error("Expected the empty folder to be deleted.")
+	}
+}
+
+func TestCascadeDeleteFolder(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, deletedFolderJSON}})
+	defer server.Close()
+
+	if err := client.CascadeDeleteFolder("nErXDvCkzz", true); err != nil {
+		t.Fatal(err)
+	}
+	if force := server.receivedRequests[0].query.Get("forceDeleteRules"); force != "true" {
+		t.Errorf("Expected forceDeleteRules to be set, got %q", force)
+	}
+}
@@ -0,0 +1,80 @@
+package gapi
+
+import (
+	"net/url"
+)
+
+// FolderTreeNode represents a Grafana folder along with its content and its subfolders.
+type FolderTreeNode struct {
+	Folder        Folder
+	Dashboards    []FolderDashboardSearchResponse
+	LibraryPanels []LibraryPanel
+	Children      []*FolderTreeNode
+}
+
+// FolderTree walks the folder hierarchy and returns the root level folders
+// along with their dashboards, library panels and subfolders.
+func (c *Client) FolderTree() ([]*FolderTreeNode, error) {
+	folders, err := c.Folders()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.folderTreeNodes(folders)
+}
+
+// FolderSubtree walks the folder hierarchy below the folder whose UID it's passed and returns it
+// along with its dashboards, library panels and subfolders.
+func (c *Client) FolderSubtree(uid string) (*FolderTreeNode, error) {
+	folder, err := c.FolderByUID(uid)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.folderTreeNode(*folder)
+}
+
+func (c *Client) folderTreeNodes(folders []Folder) ([]*FolderTreeNode, error) {
+	nodes := make([]*FolderTreeNode, 0, len(folders))
+	for _, folder := range folders {
+		node, err := c.folderTreeNode(folder)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+
+	return nodes, nil
+}
+
+func (c *Client) folderTreeNode(folder Folder) (*FolderTreeNode, error) {
+	dashboards, err := c.folderDashboardSearchAll(url.Values{
+		"type":       {"dash-db"},
+		"folderUIDs": {folder.UID},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	panels, err := c.LibraryPanelsInFolder(folder.UID)
+	if err != nil {
+		return nil, err
+	}
+
+	subfolders, err := c.ChildFolders(folder.UID)
+	if err != nil {
+		return nil, err
+	}
+
+	children, err := c.folderTreeNodes(subfolders)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FolderTreeNode{
+		Folder:        folder,
+		Dashboards:    dashboards,
+		LibraryPanels: panels,
+		Children:      children,
+	}, nil
+}
@@ -0,0 +1,109 @@
+package gapi
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gobs/pretty"
+)
+
+const (
+	folderTreeRootJSON        = `[{"id": 1, "uid": "root", "title": "Root"}]`
+	folderTreeChildJSON       = `[{"id": 2, "uid": "child", "title": "Child", "parentUid": "root"}]`
+	folderTreeDashboardsJSON  = `[{"id": 10, "uid": "dash", "title": "Dashboard", "type": "dash-db", "folderUid": "root"}]`
+	folderTreePanelsJSON      = `{"result": {"totalCount": 1, "page": 1, "perPage": 100, "elements": [{"uid": "panel", "name": "Panel", "kind": 1}]}}`
+	folderTreeEmptyPanelsJSON = `{"result": {"totalCount": 0, "page": 1, "perPage": 100, "elements": []}}`
+)
+
+func TestFolderTree(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{
+		{200, folderTreeRootJSON},
+		// root
+		{200, folderTreeDashboardsJSON},
+		{200, folderTreePanelsJSON},
+		{200, folderTreeChildJSON},
+		// child
+		{200, "[]"},
+		{200, folderTreeEmptyPanelsJSON},
+		{200, "[]"},
+	})
+	defer server.Close()
+
+	tree, err := client.FolderTree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(pretty.PrettyFormat(tree))
+
+	if len(tree) != 1 || tree[0].Folder.UID != "root" {
+		t.Fatal("Not correctly parsing root folders.")
+	}
+	root := tree[0]
+	if len(root.Dashboards) != 1 || root.Dashboards[0].UID != "dash" {
+		t.Error("Not correctly collecting folder dashboards.")
+	}
+	if len(root.LibraryPanels) != 1 || root.LibraryPanels[0].UID != "panel" {
+		t.Error("Not correctly collecting folder library panels.")
+	}
+	if len(root.Children) != 1 || root.Children[0].Folder.UID != "child" {
+		t.Fatal("Not correctly collecting subfolders.")
+	}
+	if len(root.Children[0].Dashboards) != 0 || len(root.Children[0].Children) != 0 {
+		t.Error("Expected child folder to be empty.")
+	}
+	if q := server.receivedRequests[1].query.Get("folderUIDs"); q != "root" {
+		t.Errorf("Unexpected folderUIDs query parameter: %s", q)
+	}
+}
+
+func TestFolderSubtreeDashboardsPagination(t *testing.T) {
+	firstPage := make([]string, 1000)
+	for i := range firstPage {
+		firstPage[i] = fmt.Sprintf(`{"id": %d, "uid": "dash-%d", "title": "Dashboard %d", "type": "dash-db"}`, i+1, i+1, i+1)
+	}
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{
+		{200, `{"id": 1, "uid": "root", "title": "Root"}`},
+		{200, "[" + strings.Join(firstPage, ",") + "]"},
+		{200, `[{"id": 1001, "uid": "dash-1001", "title": "Dashboard 1001", "type": "dash-db"}]`},
+		{200, folderTreeEmptyPanelsJSON},
+		{200, "[]"},
+	})
+	defer server.Close()
+
+	node, err := client.FolderSubtree("root")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(node.Dashboards) != 1001 {
+		t.Errorf("Expected 1001 dashboards, got %d", len(node.Dashboards))
+	}
+	if page := server.receivedRequests[2].query.Get("page"); page != "2" {
+		t.Errorf("Expected third request to fetch page 2 of the dashboards, got %s", page)
+	}
+}
+
+func TestFolderTreeWithoutNestedFolders(t *testing.T) {
+	// Grafana without nested folders ignores the parentUid parameter and returns the root level folders.
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{
+		{200, folderTreeRootJSON},
+		{200, "[]"},
+		{200, folderTreeEmptyPanelsJSON},
+		{200, folderTreeRootJSON},
+	})
+	defer server.Close()
+
+	tree, err := client.FolderTree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tree) != 1 || len(tree[0].Children) != 0 {
+		t.Errorf("Expected a single folder without children, got %s", pretty.PrettyFormat(tree))
+	}
+	if len(server.receivedRequests) != 4 {
+		t.Errorf("Expected 4 requests, got %d", len(server.receivedRequests))
+	}
+}
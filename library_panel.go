@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"strconv"
 	"time"
 )
 
@@ -99,6 +101,32 @@ func (c *Client) LibraryPanels() ([]LibraryPanel, error) {
 	return resp.Result.Elements, err
 }
 
+// LibraryPanelsInFolder fetches and returns all library panels stored in the folder whose UID it's passed.
+func (c *Client) LibraryPanelsInFolder(folderUID string) ([]LibraryPanel, error) {
+	const perPage = 100
+	panels := make([]LibraryPanel, 0)
+	for page := 1; ; page++ {
+		params := url.Values{
+			"kind":             {"1"},
+			"folderFilterUIDs": {folderUID},
+			"perPage":          {strconv.Itoa(perPage)},
+			"page":             {strconv.Itoa(page)},
+		}
+		resp := &struct {
+			Result LibraryPanelGetAllResponse `json:"result"`
+		}{}
+		err := c.request("GET", "/api/library-elements", params, nil, &resp)
+		if err != nil {
+			return nil, err
+		}
+
+		panels = append(panels, resp.Result.Elements...)
+		if len(resp.Result.Elements) < perPage || int64(len(panels)) >= resp.Result.TotalCount {
+			return panels, nil
+		}
+	}
+}
+
 // LibraryPanelByUID gets a library panel by UID.
 func (c *Client) LibraryPanelByUID(uid string) (*LibraryPanel, error) {
 	resp := &LibraryPanelCreateResponse{}
@@ -2,15 +2,33 @@ package gapi
 
 import (
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"sync"
 	"testing"
 )
 
+type mockServerCall struct {
+	code int
+	body string
+}
+
+type mockServerRequest struct {
+	method string
+	path   string
+	query  url.Values
+	body   string
+}
+
 type mockServer struct {
 	code   int
 	server *httptest.Server
+
+	mutex            sync.Mutex
+	upcomingCalls    []mockServerCall
+	receivedRequests []mockServerRequest
 }
 
 func (m *mockServer) Close() {
@@ -30,6 +48,49 @@ func gapiTestTools(t *testing.T, code int, body string) (*mockServer, *Client) {
 		fmt.Fprint(w, body)
 	}))
 
+	return mock, mockClient(t, mock)
+}
+
+// gapiTestToolsFromCalls returns a mock server answering each request with the next call in calls,
+// recording the requests it receives.
+func gapiTestToolsFromCalls(t *testing.T, calls []mockServerCall) (*mockServer, *Client) {
+	t.Helper()
+
+	mock := &mockServer{
+		upcomingCalls: calls,
+	}
+
+	mock.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mock.mutex.Lock()
+		defer mock.mutex.Unlock()
+
+		body, _ := ioutil.ReadAll(r.Body)
+		mock.receivedRequests = append(mock.receivedRequests, mockServerRequest{
+			method: r.Method,
+			path:   r.URL.Path,
+			query:  r.URL.Query(),
+			body:   string(body),
+		})
+
+		if len(mock.upcomingCalls) == 0 {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.String())
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		call := mock.upcomingCalls[0]
+		mock.upcomingCalls = mock.upcomingCalls[1:]
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(call.code)
+		fmt.Fprint(w, call.body)
+	}))
+
+	return mock, mockClient(t, mock)
+}
+
+func mockClient(t *testing.T, mock *mockServer) *Client {
+	t.Helper()
+
 	tr := &http.Transport{
 		Proxy: func(req *http.Request) (*url.URL, error) {
 			return url.Parse(mock.server.URL)
@@ -42,5 +103,5 @@ func gapiTestTools(t *testing.T, code int, body string) (*mockServer, *Client) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	return mock, client
+	return client
 }
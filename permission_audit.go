@@ -0,0 +1,293 @@
+package gapi
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// PermissionAuditEntry represents the effective access of a user to a folder or a dashboard.
+type PermissionAuditEntry struct {
+	ResourceKind  PermissionResourceKind `json:"resourceKind"`
+	ResourceUID   string                 `json:"resourceUid"`
+	ResourceTitle string                 `json:"resourceTitle"`
+	UserID        int64                  `json:"userId"`
+	UserLogin     string                 `json:"userLogin"`
+	UserEmail     string                 `json:"userEmail"`
+	// Level is the highest permission level the user gets from any of the sources.
+	Level PermissionLevel `json:"level"`
+	// Sources describes every grant giving the user access to the resource, e.g. "team Backend: Edit".
+	Sources []string `json:"sources"`
+}
+
+// PermissionAuditReport lists the effective access of every org user to every folder and dashboard.
+type PermissionAuditReport struct {
+	Entries []PermissionAuditEntry `json:"entries"`
+}
+
+// WriteJSON writes the report as JSON.
+func (r *PermissionAuditReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteCSV writes the report as CSV, with a header line and one line per entry.
+func (r *PermissionAuditReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := []string{"resource_kind", "resource_uid", "resource_title", "user_id", "user_login", "user_email", "permission", "sources"}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, e := range r.Entries {
+		record := []string{
+			string(e.ResourceKind),
+			e.ResourceUID,
+			e.ResourceTitle,
+			strconv.FormatInt(e.UserID, 10),
+			e.UserLogin,
+			e.UserEmail,
+			e.Level.String(),
+			strings.Join(e.Sources, "; "),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// PermissionAudit expands the team and role permissions of every folder and dashboard into per-user effective access
+// for the users of the org whose ID it's passed. If orgID is 0, the users of the current org are used.
+// Folder permissions are inherited by their subfolders and dashboards, and org admins can administer everything.
+func (c *Client) PermissionAudit(orgID int64) (*PermissionAuditReport, error) {
+	var (
+		users []OrgUser
+		err   error
+	)
+	if orgID == 0 {
+		users, err = c.OrgUsersCurrent()
+	} else {
+		users, err = c.OrgUsers(orgID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	a := &permissionAuditor{
+		client:       c,
+		users:        users,
+		teamMembers:  map[int64][]*TeamMember{},
+		teamNames:    map[int64]string{},
+		folderGrants: map[string]userGrants{},
+		report:       &PermissionAuditReport{Entries: make([]PermissionAuditEntry, 0)},
+	}
+
+	folders, err := c.Folders()
+	if err != nil {
+		return nil, err
+	}
+	for _, folder := range folders {
+		if err := a.auditFolder(folder, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	dashboards, err := c.folderDashboardSearchAll(url.Values{"type": {"dash-db"}})
+	if err != nil {
+		return nil, err
+	}
+	for _, dashboard := range dashboards {
+		if err := a.auditDashboard(dashboard); err != nil {
+			return nil, err
+		}
+	}
+
+	return a.report, nil
+}
+
+const orgAdminSource = "org role Admin"
+
+type userGrant struct {
+	level  PermissionLevel
+	source string
+}
+
+// userGrants maps user IDs to the grants giving them access to a resource.
+type userGrants map[int64][]userGrant
+
+type permissionAuditor struct {
+	client       *Client
+	users        []OrgUser
+	teamMembers  map[int64][]*TeamMember
+	teamNames    map[int64]string
+	folderGrants map[string]userGrants
+	report       *PermissionAuditReport
+}
+
+func (a *permissionAuditor) auditFolder(folder Folder, inherited userGrants) error {
+	resource := PermissionResource{Kind: PermissionResourceFolder, UID: folder.UID}
+	grants, err := a.resourceGrants(resource, inherited, fmt.Sprintf("folder %s", folder.Title))
+	if err != nil {
+		return err
+	}
+	a.folderGrants[folder.UID] = grants
+	a.addEntries(resource, folder.Title, grants)
+
+	children, err := a.client.ChildFolders(folder.UID)
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		if err := a.auditFolder(child, grants); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *permissionAuditor) auditDashboard(dashboard FolderDashboardSearchResponse) error {
+	resource := PermissionResource{Kind: PermissionResourceDashboard, UID: dashboard.UID}
+	inherited := a.folderGrants[dashboard.FolderUID]
+	grants, err := a.resourceGrants(resource, inherited, fmt.Sprintf("folder %s", dashboard.FolderTitle))
+	if err != nil {
+		return err
+	}
+	a.addEntries(resource, dashboard.Title, grants)
+
+	return nil
+}
+
+// resourceGrants expands the permissions set on the resource into per-user grants, merged with the grants
+// inherited from the parent folder. Permissions reported as inherited by the API are skipped as the parent
+// folder grants already account for them.
+func (a *permissionAuditor) resourceGrants(resource PermissionResource, inherited userGrants, parent string) (userGrants, error) {
+	permissions, err := a.client.ResourcePermissions(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	grants := userGrants{}
+	for _, user := range a.users {
+		if user.Role == "Admin" {
+			grants[user.UserID] = append(grants[user.UserID], userGrant{level: PermissionLevelAdmin, source: orgAdminSource})
+		}
+	}
+
+	for userID, inheritedGrants := range inherited {
+		for _, g := range inheritedGrants {
+			source := g.source
+			if source == orgAdminSource {
+				continue
+			}
+			if !strings.HasPrefix(source, "inherited from ") {
+				source = fmt.Sprintf("inherited from %s: %s", parent, source)
+			}
+			grants[userID] = append(grants[userID], userGrant{level: g.level, source: source})
+		}
+	}
+
+	for _, p := range permissions {
+		if p.Inherited {
+			continue
+		}
+		switch {
+		case p.Subject.UserID != 0:
+			grants[p.Subject.UserID] = append(grants[p.Subject.UserID], userGrant{
+				level:  p.Level,
+				source: fmt.Sprintf("user: %s", p.Level),
+			})
+		case p.Subject.TeamID != 0:
+			members, name, err := a.team(p.Subject.TeamID)
+			if err != nil {
+				return nil, err
+			}
+			for _, member := range members {
+				grants[member.UserID] = append(grants[member.UserID], userGrant{
+					level:  p.Level,
+					source: fmt.Sprintf("team %s: %s", name, p.Level),
+				})
+			}
+		case p.Subject.Role != "":
+			for _, user := range a.users {
+				if orgRoleIncludes(user.Role, p.Subject.Role) {
+					grants[user.UserID] = append(grants[user.UserID], userGrant{
+						level:  p.Level,
+						source: fmt.Sprintf("role %s: %s", p.Subject.Role, p.Level),
+					})
+				}
+			}
+		}
+	}
+
+	return grants, nil
+}
+
+func (a *permissionAuditor) team(id int64) ([]*TeamMember, string, error) {
+	if members, ok := a.teamMembers[id]; ok {
+		return members, a.teamNames[id], nil
+	}
+
+	team, err := a.client.Team(id)
+	if err != nil {
+		return nil, "", err
+	}
+	members, err := a.client.TeamMembers(id)
+	if err != nil {
+		return nil, "", err
+	}
+	a.teamMembers[id] = members
+	a.teamNames[id] = team.Name
+
+	return members, team.Name, nil
+}
+
+func (a *permissionAuditor) addEntries(resource PermissionResource, title string, grants userGrants) {
+	for _, user := range a.users {
+		userGrants := grants[user.UserID]
+		if len(userGrants) == 0 {
+			continue
+		}
+
+		entry := PermissionAuditEntry{
+			ResourceKind:  resource.Kind,
+			ResourceUID:   resource.UID,
+			ResourceTitle: title,
+			UserID:        user.UserID,
+			UserLogin:     user.Login,
+			UserEmail:     user.Email,
+			Sources:       make([]string, 0, len(userGrants)),
+		}
+		for _, g := range userGrants {
+			if g.level > entry.Level {
+				entry.Level = g.level
+			}
+			entry.Sources = append(entry.Sources, g.source)
+		}
+		a.report.Entries = append(a.report.Entries, entry)
+	}
+}
+
+// orgRoleIncludes returns true if users with the given org role are granted permissions set for the given role.
+func orgRoleIncludes(userRole, grantedRole string) bool {
+	ranks := map[string]int{
+		"Viewer": 1,
+		"Editor": 2,
+		"Admin":  3,
+	}
+	userRank, ok := ranks[userRole]
+	if !ok {
+		return false
+	}
+	grantedRank, ok := ranks[grantedRole]
+	if !ok {
+		return false
+	}
+	return userRank >= grantedRank
+}
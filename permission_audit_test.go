@@ -0,0 +1,126 @@
+package gapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gobs/pretty"
+)
+
+const (
+	auditOrgUsersJSON = `
+[
+  {"orgId": 1, "userId": 1, "email": "admin@localhost", "login": "admin", "role": "Admin"},
+  {"orgId": 1, "userId": 2, "email": "alice@localhost", "login": "alice", "role": "Editor"},
+  {"orgId": 1, "userId": 3, "email": "bob@localhost", "login": "bob", "role": "Viewer"}
+]
+`
+	auditFoldersJSON           = `[{"id": 1, "uid": "f1", "title": "Ops"}]`
+	auditFolderPermissionsJSON = `
+[
+  {"uid": "f1", "role": "Viewer", "permission": 1},
+  {"uid": "f1", "teamId": 5, "permission": 2}
+]
+`
+	auditTeamJSON                 = `{"id": 5, "name": "Backend"}`
+	auditTeamMembersJSON          = `[{"teamId": 5, "userId": 3, "login": "bob"}]`
+	auditDashboardsJSON           = `[{"id": 10, "uid": "d1", "title": "Latency", "type": "dash-db", "folderUid": "f1", "folderTitle": "Ops"}]`
+	auditDashboardPermissionsJSON = `
+[
+  {"dashboardId": 10, "uid": "d1", "role": "Viewer", "permission": 1, "inherited": true},
+  {"dashboardId": 10, "uid": "d1", "userId": 2, "permission": 4, "inherited": false}
+]
+`
+)
+
+func TestPermissionAudit(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{
+		{200, auditOrgUsersJSON},
+		{200, auditFoldersJSON},
+		{200, auditFolderPermissionsJSON},
+		{200, auditTeamJSON},
+		{200, auditTeamMembersJSON},
+		{200, "[]"},
+		{200, auditDashboardsJSON},
+		{200, auditDashboardPermissionsJSON},
+	})
+	defer server.Close()
+
+	report, err := client.PermissionAudit(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(pretty.PrettyFormat(report))
+
+	type access struct {
+		uid   string
+		login string
+		level PermissionLevel
+	}
+	expected := []access{
+		{"f1", "admin", PermissionLevelAdmin},
+		{"f1", "alice", PermissionLevelView},
+		{"f1", "bob", PermissionLevelEdit},
+		{"d1", "admin", PermissionLevelAdmin},
+		{"d1", "alice", PermissionLevelAdmin},
+		{"d1", "bob", PermissionLevelEdit},
+	}
+	if len(report.Entries) != len(expected) {
+		t.Fatalf("Expected %d entries, got %d", len(expected), len(report.Entries))
+	}
+	for i, e := range expected {
+		entry := report.Entries[i]
+		if entry.ResourceUID != e.uid || entry.UserLogin != e.login || entry.Level != e.level {
+			t.Errorf("Unexpected entry %d: %s %s %s", i, entry.ResourceUID, entry.UserLogin, entry.Level)
+		}
+	}
+
+	bob := report.Entries[5]
+	if len(bob.Sources) != 2 || bob.Sources[1] != "inherited from folder Ops: team Backend: Edit" {
+		t.Errorf("Unexpected sources: %v", bob.Sources)
+	}
+	if q := server.receivedRequests[6].query; q.Get("limit") != "1000" || q.Get("page") != "1" {
+		t.Errorf("Expected the dashboard search to be paged, got %v", q)
+	}
+}
+
+func TestPermissionAuditReportWriters(t *testing.T) {
+	report := &PermissionAuditReport{
+		Entries: []PermissionAuditEntry{
+			{
+				ResourceKind:  PermissionResourceFolder,
+				ResourceUID:   "f1",
+				ResourceTitle: "Ops",
+				UserID:        3,
+				UserLogin:     "bob",
+				UserEmail:     "bob@localhost",
+				Level:         PermissionLevelEdit,
+				Sources:       []string{"role Viewer: View", "team Backend: Edit"},
+			},
+		},
+	}
+
+	var csv bytes.Buffer
+	if err := report.WriteCSV(&csv); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(csv.String()), "\n")
+	if len(lines) != 2 || lines[1] != "folder,f1,Ops,3,bob,bob@localhost,Edit,role Viewer: View; team Backend: Edit" {
+		t.Errorf("Unexpected CSV output: %s", csv.String())
+	}
+
+	var out bytes.Buffer
+	if err := report.WriteJSON(&out); err != nil {
+		t.Fatal(err)
+	}
+	decoded := PermissionAuditReport{}
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Entries[0].Level != PermissionLevelEdit || !strings.Contains(out.String(), `"level": "Edit"`) {
+		t.Errorf("Unexpected JSON output: %s", out.String())
+	}
+}
@@ -0,0 +1,401 @@
+package gapi
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// PermissionResourceKind is the kind of Grafana resource permissions can be managed on.
+type PermissionResourceKind string
+
+const (
+	PermissionResourceDashboard      PermissionResourceKind = "dashboard"
+	PermissionResourceFolder         PermissionResourceKind = "folder"
+	PermissionResourceDatasource     PermissionResourceKind = "datasource"
+	PermissionResourceServiceAccount PermissionResourceKind = "serviceaccount"
+)
+
+// PermissionResource identifies a Grafana resource permissions can be managed on.
+type PermissionResource struct {
+	Kind PermissionResourceKind
+	// UID of the resource. Service accounts have no UID, their numeric ID is used instead.
+	UID string
+}
+
+// PermissionSubject identifies who a permission is granted to. Exactly one of its fields should be set.
+type PermissionSubject struct {
+	UserID int64
+	TeamID int64
+	// Role is a basic role such as Viewer or Editor.
+	Role string
+}
+
+// PermissionLevel represents a permission level, shared by every kind of resource.
+type PermissionLevel int64
+
+const (
+	// PermissionLevelView allows viewing a resource. For datasources, it allows querying them.
+	PermissionLevelView PermissionLevel = 1
+	// PermissionLevelEdit allows editing a resource.
+	PermissionLevelEdit PermissionLevel = 2
+	// PermissionLevelAdmin allows editing a resource and its permissions.
+	PermissionLevelAdmin PermissionLevel = 4
+)
+
+func (l PermissionLevel) String() string {
+	switch l {
+	case PermissionLevelView:
+		return "View"
+	case PermissionLevelEdit:
+		return "Edit"
+	case PermissionLevelAdmin:
+		return "Admin"
+	}
+	return fmt.Sprintf("PermissionLevel(%d)", int64(l))
+}
+
+// MarshalText implements the encoding.TextMarshaler interface for PermissionLevel.
+func (l PermissionLevel) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for PermissionLevel.
+func (l *PermissionLevel) UnmarshalText(text []byte) error {
+	level, err := parsePermissionLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
+// ResourcePermission represents a permission level granted to a subject on a resource.
+type ResourcePermission struct {
+	Subject PermissionSubject
+	Level   PermissionLevel
+	// Inherited is set for permissions which are not managed on the resource itself,
+	// e.g. dashboard permissions inherited from the parent folder. They are ignored by updates.
+	Inherited bool
+}
+
+// PermissionChanges represents the changes to apply to the permissions of a resource.
+type PermissionChanges struct {
+	// Grant contains permissions to add, or whose level should be changed.
+	Grant []ResourcePermission
+	// Revoke contains permissions to remove. Only their subject is considered.
+	Revoke []ResourcePermission
+}
+
+// IsEmpty returns true if there are no changes to apply.
+func (pc PermissionChanges) IsEmpty() bool {
+	return len(pc.Grant) == 0 && len(pc.Revoke) == 0
+}
+
+// DiffPermissions returns the minimal changes turning the current permissions into the desired ones.
+// Inherited permissions are ignored on both sides.
+func DiffPermissions(current, desired []ResourcePermission) PermissionChanges {
+	currentLevels := permissionLevels(current)
+	desiredLevels := permissionLevels(desired)
+
+	changes := PermissionChanges{}
+	for _, p := range desired {
+		if p.Inherited {
+			continue
+		}
+		if level, ok := currentLevels[p.Subject]; !ok || level != p.Level {
+			changes.Grant = append(changes.Grant, p)
+		}
+	}
+	for _, p := range current {
+		if p.Inherited {
+			continue
+		}
+		if _, ok := desiredLevels[p.Subject]; !ok {
+			changes.Revoke = append(changes.Revoke, p)
+		}
+	}
+
+	return changes
+}
+
+func permissionLevels(permissions []ResourcePermission) map[PermissionSubject]PermissionLevel {
+	levels := make(map[PermissionSubject]PermissionLevel, len(permissions))
+	for _, p := range permissions {
+		if !p.Inherited {
+			levels[p.Subject] = p.Level
+		}
+	}
+	return levels
+}
+
+// ResourcePermissions fetches and returns the permissions of the given resource.
+func (c *Client) ResourcePermissions(r PermissionResource) ([]ResourcePermission, error) {
+	switch r.Kind {
+	case PermissionResourceDashboard:
+		permissions, err := c.DashboardPermissionsByUID(r.UID)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]ResourcePermission, 0, len(permissions))
+		for _, p := range permissions {
+			result = append(result, ResourcePermission{
+				Subject:   PermissionSubject{UserID: p.UserID, TeamID: p.TeamID, Role: p.Role},
+				Level:     PermissionLevel(p.Permission),
+				Inherited: p.Inherited,
+			})
+		}
+		return result, nil
+
+	case PermissionResourceFolder:
+		permissions, err := c.FolderPermissions(r.UID)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]ResourcePermission, 0, len(permissions))
+		for _, p := range permissions {
+			result = append(result, ResourcePermission{
+				Subject:   PermissionSubject{UserID: p.UserID, TeamID: p.TeamID, Role: p.Role},
+				Level:     PermissionLevel(p.Permission),
+				Inherited: p.Inherited,
+			})
+		}
+		return result, nil
+
+	case PermissionResourceDatasource:
+		permissions, err := c.DatasourcePermissionsByUID(r.UID)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]ResourcePermission, 0, len(permissions))
+		for _, p := range permissions {
+			level, err := parsePermissionLevel(p.Permission)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, ResourcePermission{
+				Subject:   PermissionSubject{UserID: p.UserID, TeamID: p.TeamID, Role: p.BuiltInRole},
+				Level:     level,
+				Inherited: !p.IsManaged || p.IsInherited,
+			})
+		}
+		return result, nil
+
+	case PermissionResourceServiceAccount:
+		id, err := strconv.ParseInt(r.UID, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid service account ID %q: %w", r.UID, err)
+		}
+		permissions, err := c.GetServiceAccountPermissions(id)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]ResourcePermission, 0, len(permissions))
+		for _, p := range permissions {
+			level, err := parsePermissionLevel(p.Permission)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, ResourcePermission{
+				Subject:   PermissionSubject{UserID: p.UserID, TeamID: p.TeamID},
+				Level:     level,
+				Inherited: !p.IsManaged,
+			})
+		}
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("unsupported permission resource kind %q", r.Kind)
+}
+
+// SetResourcePermissions makes the permissions of the given resource match the desired ones,
+// applying only the changes needed. It returns the applied changes.
+func (c *Client) SetResourcePermissions(r PermissionResource, desired []ResourcePermission) (PermissionChanges, error) {
+	current, err := c.ResourcePermissions(r)
+	if err != nil {
+		return PermissionChanges{}, err
+	}
+
+	changes := DiffPermissions(current, desired)
+	return changes, c.applyPermissionChanges(r, current, changes)
+}
+
+// GrantResourcePermission grants the given permission level to the subject on the resource,
+// leaving the permissions of other subjects untouched.
+func (c *Client) GrantResourcePermission(r PermissionResource, subject PermissionSubject, level PermissionLevel) error {
+	current, err := c.ResourcePermissions(r)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range current {
+		if !p.Inherited && p.Subject == subject && p.Level == level {
+			return nil
+		}
+	}
+
+	changes := PermissionChanges{
+		Grant: []ResourcePermission{{Subject: subject, Level: level}},
+	}
+	return c.applyPermissionChanges(r, current, changes)
+}
+
+// RevokeResourcePermission removes any permission granted to the subject on the resource,
+// leaving the permissions of other subjects untouched.
+func (c *Client) RevokeResourcePermission(r PermissionResource, subject PermissionSubject) error {
+	current, err := c.ResourcePermissions(r)
+	if err != nil {
+		return err
+	}
+
+	changes := PermissionChanges{}
+	for _, p := range current {
+		if !p.Inherited && p.Subject == subject {
+			changes.Revoke = append(changes.Revoke, p)
+		}
+	}
+	return c.applyPermissionChanges(r, current, changes)
+}
+
+func (c *Client) applyPermissionChanges(r PermissionResource, current []ResourcePermission, changes PermissionChanges) error {
+	if changes.IsEmpty() {
+		return nil
+	}
+
+	switch r.Kind {
+	case PermissionResourceDashboard:
+		return c.UpdateDashboardPermissionsByUID(r.UID, replacedPermissionItems(current, changes))
+
+	case PermissionResourceFolder:
+		return c.UpdateFolderPermissions(r.UID, replacedPermissionItems(current, changes))
+
+	case PermissionResourceDatasource:
+		return c.applyDatasourcePermissionChanges(r.UID, changes)
+
+	case PermissionResourceServiceAccount:
+		return c.applyServiceAccountPermissionChanges(r.UID, changes)
+	}
+
+	return fmt.Errorf("unsupported permission resource kind %q", r.Kind)
+}
+
+// replacedPermissionItems returns the full set of permission items resulting from the changes,
+// for resources whose permissions can only be replaced as a whole.
+func replacedPermissionItems(current []ResourcePermission, changes PermissionChanges) *PermissionItems {
+	levels := permissionLevels(current)
+	subjects := make([]PermissionSubject, 0, len(current)+len(changes.Grant))
+	for _, p := range current {
+		if !p.Inherited {
+			subjects = append(subjects, p.Subject)
+		}
+	}
+	for _, p := range changes.Revoke {
+		delete(levels, p.Subject)
+	}
+	for _, p := range changes.Grant {
+		if _, ok := levels[p.Subject]; !ok {
+			subjects = append(subjects, p.Subject)
+		}
+		levels[p.Subject] = p.Level
+	}
+
+	items := &PermissionItems{Items: make([]*PermissionItem, 0, len(levels))}
+	for _, subject := range subjects {
+		level, ok := levels[subject]
+		if !ok {
+			continue
+		}
+		delete(levels, subject)
+		items.Items = append(items.Items, &PermissionItem{
+			Role:       subject.Role,
+			TeamID:     subject.TeamID,
+			UserID:     subject.UserID,
+			Permission: int64(level),
+		})
+	}
+
+	return items
+}
+
+func (c *Client) applyDatasourcePermissionChanges(uid string, changes PermissionChanges) error {
+	for _, permissions := range [][]ResourcePermission{changes.Revoke, changes.Grant} {
+		for _, p := range permissions {
+			if p.Subject == (PermissionSubject{}) {
+				return fmt.Errorf("datasource permissions require a user, team or role")
+			}
+		}
+	}
+
+	set := func(subject PermissionSubject, permission string) error {
+		switch {
+		case subject.UserID != 0:
+			return c.SetDatasourceUserPermission(uid, subject.UserID, permission)
+		case subject.TeamID != 0:
+			return c.SetDatasourceTeamPermission(uid, subject.TeamID, permission)
+		default:
+			return c.SetDatasourceBuiltInRolePermission(uid, subject.Role, permission)
+		}
+	}
+
+	for _, p := range changes.Revoke {
+		if err := set(p.Subject, ""); err != nil {
+			return err
+		}
+	}
+	for _, p := range changes.Grant {
+		permission := p.Level.String()
+		if p.Level == PermissionLevelView {
+			permission = DatasourcePermissionLevelQuery
+		}
+		if err := set(p.Subject, permission); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) applyServiceAccountPermissionChanges(uid string, changes PermissionChanges) error {
+	id, err := strconv.ParseInt(uid, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid service account ID %q: %w", uid, err)
+	}
+
+	items := &ServiceAccountPermissionItems{}
+	for _, p := range changes.Revoke {
+		if p.Subject.Role != "" {
+			return fmt.Errorf("service account permissions can't be managed for role %s", p.Subject.Role)
+		}
+		// An empty permission removes the existing one.
+		items.Permissions = append(items.Permissions, &ServiceAccountPermissionItem{
+			UserID: p.Subject.UserID,
+			TeamID: p.Subject.TeamID,
+		})
+	}
+	for _, p := range changes.Grant {
+		if p.Subject.Role != "" {
+			return fmt.Errorf("service account permissions can't be managed for role %s", p.Subject.Role)
+		}
+		if p.Level != PermissionLevelEdit && p.Level != PermissionLevelAdmin {
+			return fmt.Errorf("service account permissions can only be Edit or Admin, got %s", p.Level)
+		}
+		items.Permissions = append(items.Permissions, &ServiceAccountPermissionItem{
+			UserID:     p.Subject.UserID,
+			TeamID:     p.Subject.TeamID,
+			Permission: p.Level.String(),
+		})
+	}
+
+	return c.UpdateServiceAccountPermissions(id, items)
+}
+
+func parsePermissionLevel(name string) (PermissionLevel, error) {
+	switch name {
+	case "View", "Query":
+		return PermissionLevelView, nil
+	case "Edit":
+		return PermissionLevelEdit, nil
+	case "Admin":
+		return PermissionLevelAdmin, nil
+	}
+	return 0, fmt.Errorf("unknown permission level %q", name)
+}
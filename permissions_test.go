@@ -0,0 +1,225 @@
+package gapi
+
+import (
+	"testing"
+
+	"github.com/gobs/pretty"
+)
+
+const (
+	getResourceFolderPermissionsJSON = `
+[
+  {"uid": "nErXDvCkzz", "role": "Viewer", "permission": 1, "permissionName": "View"},
+  {"uid": "nErXDvCkzz", "teamId": 1, "permission": 2, "permissionName": "Edit"},
+  {"uid": "nErXDvCkzz", "userId": 5, "permission": 4, "permissionName": "Admin", "inherited": true}
+]
+`
+	getResourceDashboardPermissionsJSON = `
+[
+  {"dashboardId": 1, "uid": "dash", "role": "Editor", "permission": 2, "inherited": true},
+  {"dashboardId": 1, "uid": "dash", "userId": 3, "permission": 4, "inherited": false}
+]
+`
+	getResourceDatasourcePermissionsJSON = `
+[
+  {"id": 1, "roleName": "basic:admin", "builtInRole": "Admin", "isManaged": false, "permission": "Admin"},
+  {"id": 11, "roleName": "managed:teams:1:permissions", "teamId": 1, "team": "Backend", "isManaged": true, "permission": "Query"},
+  {"id": 12, "roleName": "managed:users:2:permissions", "userId": 2, "userLogin": "alice", "isManaged": true, "permission": "Edit"}
+]
+`
+	getResourceServiceAccountPermissionsJSON = `
+[
+  {"id": 1, "userId": 2, "isManaged": true, "permission": "Admin"},
+  {"id": 2, "teamId": 4, "isManaged": true, "permission": "Edit"}
+]
+`
+)
+
+func TestDiffPermissions(t *testing.T) {
+	current := []ResourcePermission{
+		{Subject: PermissionSubject{Role: "Viewer"}, Level: PermissionLevelView},
+		{Subject: PermissionSubject{TeamID: 1}, Level: PermissionLevelEdit},
+		{Subject: PermissionSubject{UserID: 2}, Level: PermissionLevelEdit},
+		{Subject: PermissionSubject{Role: "Editor"}, Level: PermissionLevelEdit, Inherited: true},
+	}
+	desired := []ResourcePermission{
+		{Subject: PermissionSubject{Role: "Viewer"}, Level: PermissionLevelView},
+		{Subject: PermissionSubject{TeamID: 1}, Level: PermissionLevelAdmin},
+		{Subject: PermissionSubject{TeamID: 2}, Level: PermissionLevelView},
+	}
+
+	changes := DiffPermissions(current, desired)
+	t.Log(pretty.PrettyFormat(changes))
+
+	if len(changes.Grant) != 2 ||
+		changes.Grant[0].Subject.TeamID != 1 || changes.Grant[0].Level != PermissionLevelAdmin ||
+		changes.Grant[1].Subject.TeamID != 2 {
+		t.Error("Not correctly computing granted permissions.")
+	}
+	if len(changes.Revoke) != 1 || changes.Revoke[0].Subject.UserID != 2 {
+		t.Error("Not correctly computing revoked permissions.")
+	}
+	if !DiffPermissions(current, current).IsEmpty() {
+		t.Error("Expected no changes between identical permissions.")
+	}
+}
+
+func TestGrantResourcePermissionFolder(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{
+		{200, getResourceFolderPermissionsJSON},
+		{200, `{"message": "Folder permissions updated"}`},
+	})
+	defer server.Close()
+
+	folder := PermissionResource{Kind: PermissionResourceFolder, UID: "nErXDvCkzz"}
+	err := client.GrantResourcePermission(folder, PermissionSubject{TeamID: 2}, PermissionLevelView)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"items":[{"role":"Viewer","permission":1},{"teamId":1,"permission":2},{"teamId":2,"permission":1}]}`
+	if body := server.receivedRequests[1].body; body != expected {
+		t.Errorf("Unexpected update body: %s", body)
+	}
+}
+
+func TestGrantResourcePermissionUnchanged(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, getResourceFolderPermissionsJSON}})
+	defer server.Close()
+
+	folder := PermissionResource{Kind: PermissionResourceFolder, UID: "nErXDvCkzz"}
+	err := client.GrantResourcePermission(folder, PermissionSubject{TeamID: 1}, PermissionLevelEdit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(server.receivedRequests) != 1 {
+		t.Error("Expected no update when the permission is already granted.")
+	}
+}
+
+func TestSetResourcePermissionsDashboard(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{
+		{200, getResourceDashboardPermissionsJSON},
+		{200, `{"message": "Dashboard permissions updated"}`},
+	})
+	defer server.Close()
+
+	dashboard := PermissionResource{Kind: PermissionResourceDashboard, UID: "dash"}
+	changes, err := client.SetResourcePermissions(dashboard, []ResourcePermission{
+		{Subject: PermissionSubject{TeamID: 7}, Level: PermissionLevelEdit},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(changes.Grant) != 1 || len(changes.Revoke) != 1 {
+		t.Errorf("Unexpected changes: %s", pretty.PrettyFormat(changes))
+	}
+	update := server.receivedRequests[1]
+	if update.path != "/api/dashboards/uid/dash/permissions" || update.body != `{"items":[{"teamId":7,"permission":2}]}` {
+		t.Errorf("Unexpected update request: %s %s", update.path, update.body)
+	}
+}
+
+func TestSetResourcePermissionsDatasource(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{
+		{200, getResourceDatasourcePermissionsJSON},
+		{200, `{"message": "Permission updated"}`},
+		{200, `{"message": "Permission updated"}`},
+	})
+	defer server.Close()
+
+	datasource := PermissionResource{Kind: PermissionResourceDatasource, UID: "ds"}
+	_, err := client.SetResourcePermissions(datasource, []ResourcePermission{
+		{Subject: PermissionSubject{TeamID: 1}, Level: PermissionLevelAdmin},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	revoke := server.receivedRequests[1]
+	if revoke.path != "/api/access-control/datasources/ds/users/2" || revoke.body != `{"permission":""}` {
+		t.Errorf("Unexpected revoke request: %s %s", revoke.path, revoke.body)
+	}
+	grant := server.receivedRequests[2]
+	if grant.path != "/api/access-control/datasources/ds/teams/1" || grant.body != `{"permission":"Admin"}` {
+		t.Errorf("Unexpected grant request: %s %s", grant.path, grant.body)
+	}
+}
+
+func TestRevokeResourcePermissionServiceAccount(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{
+		{200, getResourceServiceAccountPermissionsJSON},
+		{200, `{"message": "Permissions updated"}`},
+	})
+	defer server.Close()
+
+	serviceAccount := PermissionResource{Kind: PermissionResourceServiceAccount, UID: "8"}
+	err := client.RevokeResourcePermission(serviceAccount, PermissionSubject{TeamID: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	update := server.receivedRequests[1]
+	if update.path != "/api/access-control/serviceaccounts/8" || update.body != `{"permissions":[{"teamId":4,"permission":""}]}` {
+		t.Errorf("Unexpected update request: %s %s", update.path, update.body)
+	}
+}
+
+func TestResourcePermissionsFolderInherited(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, getResourceFolderPermissionsJSON}})
+	defer server.Close()
+
+	permissions, err := client.ResourcePermissions(PermissionResource{Kind: PermissionResourceFolder, UID: "nErXDvCkzz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(permissions) != 3 || permissions[1].Inherited || !permissions[2].Inherited {
+		t.Errorf("Unexpected permissions: %s", pretty.PrettyFormat(permissions))
+	}
+}
+
+func TestGrantResourcePermissionInvalid(t *testing.T) {
+	cases := map[string]struct {
+		resource PermissionResource
+		subject  PermissionSubject
+		level    PermissionLevel
+		current  string
+	}{
+		"service account view": {
+			resource: PermissionResource{Kind: PermissionResourceServiceAccount, UID: "8"},
+			subject:  PermissionSubject{UserID: 3},
+			level:    PermissionLevelView,
+			current:  getResourceServiceAccountPermissionsJSON,
+		},
+		"datasource empty subject": {
+			resource: PermissionResource{Kind: PermissionResourceDatasource, UID: "ds"},
+			level:    PermissionLevelEdit,
+			current:  getResourceDatasourcePermissionsJSON,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			server, client := gapiTestToolsFromCalls(t, []mockServerCall{{200, c.current}})
+			defer server.Close()
+
+			if err := client.GrantResourcePermission(c.resource, c.subject, c.level); err == nil {
+				t.Error("Expected an error.")
+			}
+			if len(server.receivedRequests) != 1 {
+				t.Errorf("Expected no update request, got %d requests", len(server.receivedRequests))
+			}
+		})
+	}
+}
+
+func TestResourcePermissionsUnsupportedKind(t *testing.T) {
+	server, client := gapiTestToolsFromCalls(t, nil)
+	defer server.Close()
+
+	_, err := client.ResourcePermissions(PermissionResource{Kind: "playlist", UID: "abc"})
+	if err == nil {
+		t.Error("Expected an error for an unsupported resource kind.")
+	}
+}